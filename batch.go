@@ -0,0 +1,59 @@
+package graco
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// SendBatch sends all values over the edge.
+// It uses BatchEdge.SendBatch if the edge supports it and falls back to sending values one by one otherwise.
+// Returns the number of values sent. Values that were not sent are still owned by the caller.
+func SendBatch[T any](ctx context.Context, e SourceEdge[T], vals []T) (int, error) {
+	if be, ok := e.(BatchEdge[T]); ok {
+		return be.SendBatch(ctx, vals)
+	}
+	for i, val := range vals {
+		if err := e.Send(ctx, val); err != nil {
+			return i, err
+		}
+	}
+	return len(vals), nil
+}
+
+// RecvBatch receives up to max values from the edge using the timeout-or-count policy of BatchEdge.RecvBatch.
+// It uses BatchEdge.RecvBatch if the edge supports it and falls back to receiving values one by one otherwise.
+func RecvBatch[T any](ctx context.Context, e SourceEdge[T], max int, linger time.Duration) ([]T, error) {
+	if be, ok := e.(BatchEdge[T]); ok {
+		return be.RecvBatch(ctx, max, linger)
+	}
+
+	first, err := e.Recv(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if max < 1 {
+		max = 1
+	}
+	res := make([]T, 1, max)
+	res[0] = first
+	if linger <= 0 {
+		return res, nil
+	}
+
+	lctx, cancel := context.WithTimeout(ctx, linger)
+	defer cancel()
+	for len(res) < max {
+		val, err := e.Recv(lctx)
+		if err == nil {
+			res = append(res, val)
+			continue
+		}
+		if errors.Is(err, io.EOF) || lctx.Err() != nil {
+			break
+		}
+		return res, err
+	}
+	return res, nil
+}
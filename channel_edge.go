@@ -4,18 +4,26 @@ import (
 	"context"
 	"errors"
 	"io"
+	"sync"
+	"time"
 )
 
 var (
 	_ SourceEdge[int]               = (*ChannelSourceEdge[int])(nil)
+	_ BatchEdge[int]                = (*ChannelSourceEdge[int])(nil)
+	_ Queue                         = (*ChannelSourceEdge[int])(nil)
+	_ Disconnecter                  = (*ChannelSourceEdge[int])(nil)
 	_ DestinationEdge[int, float32] = (*ChannelDestinationEdge[int, float32])(nil)
 )
 
 // ChannelSourceEdge is a basic implementation of the StreamingEdge[T] interface using channels.
 type ChannelSourceEdge[T any] struct {
-	name     string
-	src, dst Node
-	ch       chan T
+	name      string
+	src, dst  Node
+	ch        chan T
+	closeOnce sync.Once
+	gone      chan struct{} // closed when the destination node is disconnected
+	goneOnce  sync.Once
 }
 
 // ChannelDestinationEdge is an extention.
@@ -27,7 +35,7 @@ type ChannelDestinationEdge[T, Tresp any] struct {
 // NewSourceEdge creates an ChannelSourceEdge[T] instance that satisfies SourceEdge[T] interface.
 // prefix: Prefix string for naming the edge
 // cap: Capacity of the underlying channel (buffer size)
-// prime: Whether to prime the channel with a zero value
+// prime: Whether to prime the channel with a zero value. Use NewFeedbackEdge for loops.
 func NewSourceEdge[T any](name string, src Node, cap int, prime bool) (*ChannelSourceEdge[T], error) {
 	if cap < 0 {
 		cap = 0
@@ -36,6 +44,7 @@ func NewSourceEdge[T any](name string, src Node, cap int, prime bool) (*ChannelS
 		name: name,
 		src:  src,
 		ch:   make(chan T, cap),
+		gone: make(chan struct{}),
 	}
 	if prime && cap > 0 {
 		var zero T
@@ -44,8 +53,9 @@ func NewSourceEdge[T any](name string, src Node, cap int, prime bool) (*ChannelS
 	return res, nil
 }
 
-// NewDestinationEdgeBuilder creates an EdgeBuilder for creating Edge instances that satisfy SourceEdge and DestinationEdge interfaces.
-// cap: Capacity of the underlying channel (buffer size)
+// NewDestinationEdge creates a ChannelDestinationEdge[T, Tresp] instance that satisfies DestinationEdge[T, Tresp] interface.
+// The reply edge flows in the opposite direction: its source is the destination node and its destination is src.
+// cap: Capacity of the underlying channels (buffer size)
 func NewDestinationEdge[T, Tresp any](prefix string, src Node, cap int) (*ChannelDestinationEdge[T, Tresp], error) {
 	if cap < 0 {
 		cap = 0
@@ -54,10 +64,11 @@ func NewDestinationEdge[T, Tresp any](prefix string, src Node, cap int) (*Channe
 	if err != nil {
 		return nil, err
 	}
-	de, err := NewSourceEdge[Tresp](prefix, src, cap, false)
+	de, err := NewSourceEdge[Tresp](prefix+".reply", nil, cap, false)
 	if err != nil {
 		return nil, err
 	}
+	de.dst = src
 	res := &ChannelDestinationEdge[T, Tresp]{
 		ChannelSourceEdge: se,
 		reply:             de,
@@ -72,19 +83,47 @@ func (e *ChannelSourceEdge[T]) Connect(dst Node) error {
 	return nil
 }
 func (e *ChannelSourceEdge[T]) C() chan T { return e.ch }
+
+// Len returns the number of buffered values.
+func (e *ChannelSourceEdge[T]) Len() int { return len(e.ch) }
+
+// Disconnect makes further sends return io.EOF and releases buffered values.
+// It is called by the graph when the destination node finishes.
+func (e *ChannelSourceEdge[T]) Disconnect() error {
+	e.goneOnce.Do(func() {
+		close(e.gone)
+	})
+	return e.Drain()
+}
+
+// Disconnected returns a channel that is closed once the edge is disconnected.
+// Source nodes that write to C directly must stop writing when it is closed.
+func (e *ChannelSourceEdge[T]) Disconnected() <-chan struct{} { return e.gone }
+
+// Close closes the underlying channel. It must be called by the source node only and is idempotent.
 func (e *ChannelSourceEdge[T]) Close() error {
-	close(e.ch)
+	e.closeOnce.Do(func() {
+		close(e.ch)
+	})
 	return nil
 }
 
+// Send sends a value over the edge. Returns io.EOF if the edge is disconnected, in which case the value was not sent.
 func (e *ChannelSourceEdge[T]) Send(ctx context.Context, val T) error {
 	if e.dst == nil {
 		return errors.New("output disconnected")
 	}
 
+	select {
+	case <-e.gone:
+		return io.EOF
+	default:
+	}
 	select {
 	case <-ctx.Done():
 		return context.Cause(ctx)
+	case <-e.gone:
+		return io.EOF
 	case e.ch <- val:
 	}
 	return nil
@@ -107,6 +146,89 @@ func (e *ChannelSourceEdge[T]) Recv(ctx context.Context) (T, error) {
 	}
 }
 
+func (e *ChannelSourceEdge[T]) SendBatch(ctx context.Context, vals []T) (int, error) {
+	if e.dst == nil {
+		return 0, errors.New("output disconnected")
+	}
+
+	select {
+	case <-e.gone:
+		return 0, io.EOF
+	default:
+	}
+	for i, val := range vals {
+		select {
+		case e.ch <- val:
+			continue
+		default:
+		}
+		select {
+		case <-ctx.Done():
+			return i, context.Cause(ctx)
+		case <-e.gone:
+			return i, io.EOF
+		case e.ch <- val:
+		}
+	}
+	return len(vals), nil
+}
+
+func (e *ChannelSourceEdge[T]) RecvBatch(ctx context.Context, max int, linger time.Duration) ([]T, error) {
+	first, err := e.Recv(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if max < 1 {
+		max = 1
+	}
+	res := make([]T, 1, max)
+	res[0] = first
+
+	// Collect values that are immediately available first to avoid arming a timer.
+	for len(res) < max {
+		select {
+		case c, ok := <-e.ch:
+			if !ok {
+				return res, nil
+			}
+			res = append(res, c)
+			continue
+		default:
+		}
+		break
+	}
+	if len(res) >= max || linger <= 0 {
+		return res, nil
+	}
+
+	timer := time.NewTimer(linger)
+	defer timer.Stop()
+	for len(res) < max {
+		select {
+		case <-ctx.Done():
+			return res, nil
+		case <-timer.C:
+			return res, nil
+		case c, ok := <-e.ch:
+			if !ok {
+				return res, nil
+			}
+			res = append(res, c)
+		}
+	}
+	return res, nil
+}
+
+// Connect populates destination node reference of the edge and the source node reference of the reply edge.
+func (e *ChannelDestinationEdge[T, Tr]) Connect(dst Node) error {
+	e.dst = dst
+	e.reply.src = dst
+	return nil
+}
+
+// Replies returns true since the destination node sends replies to the source node.
+func (e *ChannelDestinationEdge[T, Tr]) Replies() bool { return true }
+
 func (e *ChannelDestinationEdge[T, Tr]) Reply() (SourceEdge[Tr], error) {
 	return e.reply, nil
 }
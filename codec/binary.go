@@ -0,0 +1,48 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+var (
+	_ Codec[int32]    = binaryCodec[int32]{}
+	_ Appender[int32] = binaryCodec[int32]{}
+)
+
+type binaryCodec[T any] struct {
+	order binary.ByteOrder
+	size  int
+}
+
+func (c binaryCodec[T]) Marshal(val T) ([]byte, error) {
+	return c.AppendMarshal(make([]byte, 0, c.size), val)
+}
+func (c binaryCodec[T]) AppendMarshal(dst []byte, val T) ([]byte, error) {
+	w := appendWriter{buf: dst}
+	err := binary.Write(&w, c.order, val)
+	return w.buf, err
+}
+func (c binaryCodec[T]) Unmarshal(data []byte) (T, error) {
+	var val T
+	if len(data) != c.size {
+		return val, fmt.Errorf("binary: expected %d bytes, got %d", c.size, len(data))
+	}
+	err := binary.Read(bytes.NewReader(data), c.order, &val)
+	return val, err
+}
+
+// Binary creates a codec that uses encoding/binary with the given byte order.
+// T must be a fixed-size value: a number, a bool, or an array or struct of fixed-size values.
+func Binary[T any](order binary.ByteOrder) (binaryCodec[T], error) {
+	var zero T
+	size := binary.Size(zero)
+	if size < 0 {
+		return binaryCodec[T]{}, fmt.Errorf("binary: %T is not a fixed-size value", zero)
+	}
+	return binaryCodec[T]{
+		order: order,
+		size:  size,
+	}, nil
+}
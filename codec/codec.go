@@ -0,0 +1,26 @@
+// Package codec provides pluggable value serialization used by edges, processors, recorders and file sinks.
+//
+// A Codec converts a single value to bytes and back. A Framer delimits encoded values in a byte stream.
+package codec
+
+// Codec marshals values of type T into bytes and back.
+type Codec[T any] interface {
+	Marshal(T) ([]byte, error)
+	Unmarshal([]byte) (T, error)
+}
+
+// Appender is implemented by codecs that can marshal into an existing buffer, e.g. one taken from a pool.
+type Appender[T any] interface {
+	// AppendMarshal appends the encoded value to dst and returns the extended buffer.
+	AppendMarshal(dst []byte, val T) ([]byte, error)
+}
+
+// appendWriter is an io.Writer that appends to a byte slice.
+type appendWriter struct {
+	buf []byte
+}
+
+func (w *appendWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	return len(p), nil
+}
@@ -0,0 +1,74 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+)
+
+var (
+	_ Codec[[]string]    = csvCodec[[]string]{}
+	_ Appender[[]string] = csvCodec[[]string]{}
+)
+
+type csvCodec[T any] struct {
+	comma  rune
+	encode func(T) ([]string, error)
+	decode func([]string) (T, error)
+}
+
+func (c csvCodec[T]) Marshal(val T) ([]byte, error) { return c.AppendMarshal(nil, val) }
+
+// AppendMarshal appends a single CSV row without the terminating newline.
+func (c csvCodec[T]) AppendMarshal(dst []byte, val T) ([]byte, error) {
+	row, err := c.encode(val)
+	if err != nil {
+		return dst, err
+	}
+	w := appendWriter{buf: dst}
+	cw := csv.NewWriter(&w)
+	cw.Comma = c.comma
+	if err := cw.Write(row); err != nil {
+		return dst, err
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return dst, err
+	}
+	return w.buf[:len(w.buf)-1], nil
+}
+
+func (c csvCodec[T]) Unmarshal(data []byte) (T, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = c.comma
+	r.FieldsPerRecord = -1
+	rows, err := r.ReadAll()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	if len(rows) != 1 {
+		var zero T
+		return zero, fmt.Errorf("csv: expected 1 row, got %d", len(rows))
+	}
+	return c.decode(rows[0])
+}
+
+// CSV creates a codec that encodes a value as a single CSV row.
+// encode and decode convert between the value and the fields of the row.
+func CSV[T any](comma rune, encode func(T) ([]string, error), decode func([]string) (T, error)) csvCodec[T] {
+	return csvCodec[T]{
+		comma:  comma,
+		encode: encode,
+		decode: decode,
+	}
+}
+
+// CSVRows creates a codec that encodes rows of fields.
+func CSVRows(comma rune) csvCodec[[]string] {
+	return CSV[[]string](
+		comma,
+		func(row []string) ([]string, error) { return row, nil },
+		func(row []string) ([]string, error) { return row, nil },
+	)
+}
@@ -0,0 +1,139 @@
+package codec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+)
+
+var (
+	_ Framer = lengthFramer{}
+	_ Framer = lineFramer{}
+)
+
+var (
+	ErrFrameTooLarge = errors.New("frame too large")
+	ErrInvalidFrame  = errors.New("invalid frame")
+)
+
+// Framer delimits encoded values in a byte stream.
+type Framer interface {
+	// WriteFrame writes a single frame containing data.
+	WriteFrame(w io.Writer, data []byte) error
+	// ReadFrame reads a single frame and appends its contents to dst.
+	// Returns io.EOF if the stream ended at a frame boundary and io.ErrUnexpectedEOF if it ended inside a frame.
+	ReadFrame(r *bufio.Reader, dst []byte) ([]byte, error)
+}
+
+type lengthFramer struct {
+	max int
+}
+
+func (f lengthFramer) WriteFrame(w io.Writer, data []byte) error {
+	if len(data) > f.max {
+		return ErrFrameTooLarge
+	}
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(data)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+func (f lengthFramer) ReadFrame(r *bufio.Reader, dst []byte) ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return dst, err
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	if uint64(size) > uint64(f.max) {
+		return dst, ErrFrameTooLarge
+	}
+	n := len(dst)
+	if cap(dst)-n < int(size) {
+		grown := make([]byte, n, n+int(size))
+		copy(grown, dst)
+		dst = grown
+	}
+	dst = dst[:n+int(size)]
+	if _, err := io.ReadFull(r, dst[n:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return dst[:n], err
+	}
+	return dst, nil
+}
+
+// LengthPrefixed creates a framer that prefixes every frame with its length as a big endian uint32.
+// max: Maximum frame size in bytes. Non-positive max selects 64 KiB.
+func LengthPrefixed(max int) lengthFramer {
+	if max <= 0 {
+		max = bufio.MaxScanTokenSize
+	}
+	// The length is a uint32, and frames must also fit in an int on 32-bit platforms.
+	limit := uint64(math.MaxUint32)
+	if strconv.IntSize == 32 {
+		limit = math.MaxInt32
+	}
+	if uint64(max) < limit {
+		limit = uint64(max)
+	}
+	return lengthFramer{max: int(limit)}
+}
+
+type lineFramer struct {
+	max int
+}
+
+func (f lineFramer) WriteFrame(w io.Writer, data []byte) error {
+	if bytes.IndexByte(data, '\n') >= 0 {
+		return ErrInvalidFrame
+	}
+	if len(data) > f.max {
+		return ErrFrameTooLarge
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	_, err := w.Write([]byte{'\n'})
+	return err
+}
+
+func (f lineFramer) ReadFrame(r *bufio.Reader, dst []byte) ([]byte, error) {
+	n := len(dst)
+	for {
+		line, err := r.ReadSlice('\n')
+		dst = append(dst, line...)
+		if len(dst)-n > f.max+1 {
+			return dst[:n], ErrFrameTooLarge
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(dst) > n {
+				// The last line is not terminated.
+				return dst, nil
+			}
+			return dst[:n], err
+		}
+		return dst[:len(dst)-1], nil
+	}
+}
+
+// Lines creates a framer that terminates every frame with a newline. Frames must not contain newlines,
+// which suits text encodings such as JSON and CSV. A trailing carriage return is not removed.
+// max: Maximum frame size in bytes. Non-positive max selects 64 KiB.
+func Lines(max int) lineFramer {
+	if max <= 0 {
+		max = bufio.MaxScanTokenSize
+	}
+	return lineFramer{max: max}
+}
@@ -0,0 +1,31 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+var (
+	_ Codec[int]    = gobCodec[int]{}
+	_ Appender[int] = gobCodec[int]{}
+)
+
+type gobCodec[T any] struct{}
+
+func (c gobCodec[T]) Marshal(val T) ([]byte, error) { return c.AppendMarshal(nil, val) }
+func (gobCodec[T]) AppendMarshal(dst []byte, val T) ([]byte, error) {
+	w := appendWriter{buf: dst}
+	err := gob.NewEncoder(&w).Encode(val)
+	return w.buf, err
+}
+func (gobCodec[T]) Unmarshal(data []byte) (T, error) {
+	var val T
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&val)
+	return val, err
+}
+
+// Gob creates a codec that uses encoding/gob.
+// Every value is encoded with its type information, so that values can be decoded independently.
+func Gob[T any]() gobCodec[T] {
+	return gobCodec[T]{}
+}
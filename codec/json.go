@@ -0,0 +1,30 @@
+package codec
+
+import "encoding/json"
+
+var (
+	_ Codec[int]    = jsonCodec[int]{}
+	_ Appender[int] = jsonCodec[int]{}
+)
+
+type jsonCodec[T any] struct{}
+
+func (jsonCodec[T]) Marshal(val T) ([]byte, error) { return json.Marshal(val) }
+func (jsonCodec[T]) AppendMarshal(dst []byte, val T) ([]byte, error) {
+	w := appendWriter{buf: dst}
+	if err := json.NewEncoder(&w).Encode(val); err != nil {
+		return dst, err
+	}
+	// Encode terminates the value with a newline unlike json.Marshal.
+	return w.buf[:len(w.buf)-1], nil
+}
+func (jsonCodec[T]) Unmarshal(data []byte) (T, error) {
+	var val T
+	err := json.Unmarshal(data, &val)
+	return val, err
+}
+
+// JSON creates a codec that uses encoding/json.
+func JSON[T any]() jsonCodec[T] {
+	return jsonCodec[T]{}
+}
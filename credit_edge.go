@@ -0,0 +1,145 @@
+package graco
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	_ BatchEdge[int] = (*CreditEdge[int])(nil)
+	_ Crediter       = (*CreditEdge[int])(nil)
+)
+
+// Crediter is an interface implemented by edges with credit based flow control.
+type Crediter interface {
+	// Credit returns the number of values the producer may send without blocking.
+	Credit() int
+}
+
+// CreditEdge is a ChannelDestinationEdge with credit based backpressure.
+// The consumer grants credits to the producer through the reply edge and the producer sends only while it has credit.
+type CreditEdge[T any] struct {
+	*ChannelDestinationEdge[T, int]
+	credit atomic.Int64
+	auto   bool
+}
+
+// NewCreditEdge creates a CreditEdge[T] instance that satisfies SourceEdge[T] and Crediter interfaces.
+// window: Number of credits initially granted to the producer. It is also the capacity of the underlying channels.
+// auto: Whether Recv grants one credit back for each received value.
+func NewCreditEdge[T any](name string, src Node, window int, auto bool) (*CreditEdge[T], error) {
+	if window < 1 {
+		window = 1
+	}
+	de, err := NewDestinationEdge[T, int](name, src, window)
+	if err != nil {
+		return nil, err
+	}
+	res := &CreditEdge[T]{
+		ChannelDestinationEdge: de,
+		auto:                   auto,
+	}
+	res.credit.Store(int64(window))
+	return res, nil
+}
+
+// Credit collects pending grants and returns the number of values the producer may send without blocking. Used by source node.
+func (e *CreditEdge[T]) Credit() int {
+	for {
+		select {
+		case n, ok := <-e.reply.ch:
+			if ok {
+				e.credit.Add(int64(n))
+				continue
+			}
+		default:
+		}
+		return int(e.credit.Load())
+	}
+}
+
+// Grant grants n credits to the producer. Used by destination node.
+func (e *CreditEdge[T]) Grant(ctx context.Context, n int) error {
+	if n <= 0 {
+		return nil
+	}
+	select {
+	case e.reply.ch <- n:
+		return nil
+	default:
+	}
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case e.reply.ch <- n:
+	}
+	return nil
+}
+
+// Replies returns false since credit grants control the flow rather than carry values.
+func (e *CreditEdge[T]) Replies() bool { return false }
+
+// Send waits for credit and sends a value over the edge.
+func (e *CreditEdge[T]) Send(ctx context.Context, val T) error {
+	if err := e.acquire(ctx); err != nil {
+		return err
+	}
+	return e.ChannelSourceEdge.Send(ctx, val)
+}
+
+// TrySend sends a value only if there is credit available. Returns false if the value was not sent.
+func (e *CreditEdge[T]) TrySend(ctx context.Context, val T) (bool, error) {
+	if e.Credit() <= 0 {
+		return false, nil
+	}
+	e.credit.Add(-1)
+	return true, e.ChannelSourceEdge.Send(ctx, val)
+}
+
+func (e *CreditEdge[T]) SendBatch(ctx context.Context, vals []T) (int, error) {
+	for i, val := range vals {
+		if err := e.Send(ctx, val); err != nil {
+			return i, err
+		}
+	}
+	return len(vals), nil
+}
+
+// Recv receives a value and grants a credit back if the edge was created with automatic grants.
+func (e *CreditEdge[T]) Recv(ctx context.Context) (T, error) {
+	val, err := e.ChannelSourceEdge.Recv(ctx)
+	if err != nil || !e.auto {
+		return val, err
+	}
+	return val, e.Grant(ctx, 1)
+}
+
+func (e *CreditEdge[T]) RecvBatch(ctx context.Context, max int, linger time.Duration) ([]T, error) {
+	vals, err := e.ChannelSourceEdge.RecvBatch(ctx, max, linger)
+	if err != nil || !e.auto {
+		return vals, err
+	}
+	return vals, e.Grant(ctx, len(vals))
+}
+
+// acquire takes one credit waiting for grants if there is none.
+func (e *CreditEdge[T]) acquire(ctx context.Context) error {
+	for e.Credit() <= 0 {
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-e.gone:
+			return io.EOF
+		case n, ok := <-e.reply.ch:
+			if !ok {
+				return errors.New("credit edge closed")
+			}
+			e.credit.Add(int64(n))
+		}
+	}
+	e.credit.Add(-1)
+	return nil
+}
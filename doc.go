@@ -12,9 +12,24 @@
 // Key primitives:
 // - Source
 // - Sink
-// - Processor
+// - Processor, including an ordered parallel processor and an autoscaling worker pool
+// - Processor middleware: retry, timeout, circuit breaker and bulkhead
+// - Memoizing processor cache
+// - Operators: Map, Filter, Scan, Reduce, Take, Skip, TakeWhile and Distinct
 // - FanIn with several synchronizers
 // - FanOut
+// - Topic with dynamic subscribers
+// - KeyBy and keyed stateful processors, see the keyed package
+// - Windows by count, processing time or event time, see the window package
 // - Tickers
 // - Rate Limiters
+// - Envelopes carrying metadata through the nodes
+// - Encode/Decode processors and framed byte streams built on the codec package
+//
+// Edges:
+// - ChannelSourceEdge, ChannelDestinationEdge
+// - RingEdge for high-throughput single-producer/single-consumer links
+// - CreditEdge for credit based backpressure
+// - MultiSourceEdge merging several producers
+// - ChannelFeedbackEdge closing loops, see source.Feedback
 package graco
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"time"
 )
 
 // Edge is an interface that provides method for edges
@@ -24,6 +25,42 @@ type EdgeStarter interface {
 	Start(context.Context) error
 }
 
+// MultiProducerEdge is an interface that extends the Edge interface for edges written by several source nodes.
+type MultiProducerEdge interface {
+	Edge
+	// Attach registers a source node. Every attached source node must Close the edge when it finishes.
+	Attach(Node) error
+	// Producers returns all attached source nodes.
+	Producers() []Node
+}
+
+// Queue is an interface implemented by buffered edges that report the number of values waiting to be received.
+type Queue interface {
+	Len() int
+}
+
+// Replier is an interface implemented by edges whose destination node may send values back to the source node.
+type Replier interface {
+	Edge
+	// Replies reports whether the destination node sends values back over a reply edge.
+	Replies() bool
+}
+
+// Disconnecter is an interface implemented by edges that can be disconnected once their destination node finishes.
+// Further sends return io.EOF so that the source node finishes too, and buffered values are released.
+type Disconnecter interface {
+	Disconnect() error
+}
+
+// FeedbackEdge is an interface that extends the Edge interface for edges that close a loop in the graph.
+// Feedback edges are ignored by loop validation and by the closing order of the graph.
+type FeedbackEdge interface {
+	Edge
+	// Break releases the loop once the destination node has finished.
+	// Buffered values are discarded and further sends return io.EOF so that the source node can finish too.
+	Break() error
+}
+
 // SourceEdge is an interface that extends the Edge interface and provides methods for sending and receiving values of type T from source to destination.
 type SourceEdge[T any] interface {
 	Edge
@@ -35,6 +72,19 @@ type SourceEdge[T any] interface {
 	Recv(context.Context) (T, error)
 }
 
+// BatchEdge is an interface that extends the SourceEdge[T] interface and provides methods for sending and receiving batches of values.
+// Batches amortize the synchronization and context overhead of per-value transfers.
+type BatchEdge[T any] interface {
+	SourceEdge[T]
+	// SendBatch sends all values over the edge. Used by source node.
+	// Returns the number of values sent, which is less than the number of values only if an error occurred.
+	SendBatch(context.Context, []T) (int, error)
+	// RecvBatch receives up to max values from the edge. Used by destination node.
+	// It blocks until the first value arrives and then collects values until either max values are received or linger elapses.
+	// Zero linger returns only the values that are immediately available.
+	RecvBatch(ctx context.Context, max int, linger time.Duration) ([]T, error)
+}
+
 // DestinationEdge is an interface that extends the SourceEdge[T] interface and provides methods for returning an edge used to reply by destination node.
 type DestinationEdge[T, Tresp any] interface {
 	SourceEdge[T]
@@ -0,0 +1,142 @@
+package graco
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrNotCloneable is returned by Envelope.Clone when the value is an io.Closer that can be neither cloned nor retained.
+var ErrNotCloneable = errors.New("value not cloneable")
+
+// Envelope carries a value together with metadata that is propagated by the built-in nodes.
+// Headers must be treated as immutable. Use WithHeader to obtain an envelope with modified headers.
+type Envelope[T any] struct {
+	// Seq is the sequence number assigned by the source.
+	Seq uint64
+	// Created is the creation time of the original value.
+	Created time.Time
+	// TraceID identifies the value across nodes.
+	TraceID string
+	// Deadline is the time after which the value is stale. Zero means no deadline.
+	Deadline time.Time
+	// Headers are free-form key/value pairs.
+	Headers map[string]string
+	Val     T
+}
+
+// NewEnvelope wraps a value into an Envelope created now.
+func NewEnvelope[T any](val T) Envelope[T] {
+	return Envelope[T]{
+		Created: time.Now(),
+		Val:     val,
+	}
+}
+
+// Rewrap returns an envelope carrying val with the metadata of e.
+func Rewrap[T, To any](e Envelope[T], val To) Envelope[To] {
+	return Envelope[To]{
+		Seq:      e.Seq,
+		Created:  e.Created,
+		TraceID:  e.TraceID,
+		Deadline: e.Deadline,
+		Headers:  e.Headers,
+		Val:      val,
+	}
+}
+
+// MergeEnvelope returns an envelope carrying val with the metadata of a merged with the metadata of b.
+// Sequence number is taken from a, trace ID is taken from a unless empty, creation time and deadline are the earliest of both
+// and headers of a take precedence over headers of b.
+func MergeEnvelope[A, B, To any](a Envelope[A], b Envelope[B], val To) Envelope[To] {
+	res := Rewrap(a, val)
+	if res.TraceID == "" {
+		res.TraceID = b.TraceID
+	}
+	if res.Created.IsZero() || (!b.Created.IsZero() && b.Created.Before(res.Created)) {
+		res.Created = b.Created
+	}
+	if res.Deadline.IsZero() || (!b.Deadline.IsZero() && b.Deadline.Before(res.Deadline)) {
+		res.Deadline = b.Deadline
+	}
+	if len(b.Headers) > 0 {
+		headers := make(map[string]string, len(a.Headers)+len(b.Headers))
+		for k, v := range b.Headers {
+			headers[k] = v
+		}
+		for k, v := range a.Headers {
+			headers[k] = v
+		}
+		res.Headers = headers
+	}
+	return res
+}
+
+// Timestamp returns the creation time as a duration since Unix epoch. It satisfies fanin.WithTimestamp.
+func (e Envelope[T]) Timestamp() time.Duration {
+	return time.Duration(e.Created.UnixNano())
+}
+
+// Header returns the value of a header or an empty string.
+func (e Envelope[T]) Header(key string) string {
+	return e.Headers[key]
+}
+
+// WithHeader returns a copy of the envelope with the header set.
+func (e Envelope[T]) WithHeader(key, val string) Envelope[T] {
+	headers := make(map[string]string, len(e.Headers)+1)
+	for k, v := range e.Headers {
+		headers[k] = v
+	}
+	headers[key] = val
+	e.Headers = headers
+	return e
+}
+
+// Clone copies the envelope including headers. Reference counted values implementing Retainer are retained,
+// values implementing Clone() (T, error) are cloned and other values are copied, unless they implement io.Closer,
+// in which case ErrNotCloneable is returned rather than sharing the value.
+func (e Envelope[T]) Clone() (Envelope[T], error) {
+	if len(e.Headers) > 0 {
+		headers := make(map[string]string, len(e.Headers))
+		for k, v := range e.Headers {
+			headers[k] = v
+		}
+		e.Headers = headers
+	}
+	if r, ok := any(e.Val).(Retainer); ok {
+		r.Retain()
+		return e, nil
+	}
+	if cloner, ok := any(e.Val).(interface{ Clone() (T, error) }); ok {
+		val, err := cloner.Clone()
+		if err != nil {
+			return e, err
+		}
+		e.Val = val
+		return e, nil
+	}
+	if _, ok := any(e.Val).(io.Closer); ok {
+		return e, ErrNotCloneable
+	}
+	return e, nil
+}
+
+// Close closes the value if it implements io.Closer.
+func (e Envelope[T]) Close() error {
+	if closer, ok := any(e.Val).(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// NewTraceID generates a random trace ID.
+func NewTraceID() string {
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(id[:])
+}
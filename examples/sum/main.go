@@ -76,8 +76,8 @@ func main() {
 
 	go func() {
 		err = g.Start(ctx)
-		log.Println("Start finished with: ", err.Error())
-		if err != nil {
+		log.Println("Start finished with: ", err)
+		if err := g.Close(); err != nil {
 			panic(err)
 		}
 	}()
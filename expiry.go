@@ -0,0 +1,28 @@
+package graco
+
+import "time"
+
+var (
+	_ Expirer = Envelope[int]{}
+)
+
+// Expirer is an interface implemented by values that carry a deadline.
+type Expirer interface {
+	// Expired reports whether the value is stale at the given time.
+	Expired(now time.Time) bool
+}
+
+// Expired reports whether the envelope deadline has passed.
+func (e Envelope[T]) Expired(now time.Time) bool {
+	return !e.Deadline.IsZero() && now.After(e.Deadline)
+}
+
+// DropExpired reports whether val implements Expirer and has expired.
+// Expired values are released.
+func DropExpired(val any) (bool, error) {
+	expirer, ok := val.(Expirer)
+	if !ok || !expirer.Expired(time.Now()) {
+		return false, nil
+	}
+	return true, Release(val)
+}
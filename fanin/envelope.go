@@ -0,0 +1,23 @@
+package fanin
+
+import "github.com/itohio/graco"
+
+var (
+	_ WithTimestamp = graco.Envelope[int]{}
+)
+
+// EnvelopePair adapts a pair maker of plain values to join envelopes. Metadata of both envelopes is merged into the result.
+func EnvelopePair[A, B, Res any](make PairMakerFunc[A, B, Res]) PairMakerFunc[graco.Envelope[A], graco.Envelope[B], graco.Envelope[Res]] {
+	return func(a graco.Envelope[A], b graco.Envelope[B]) (graco.Envelope[Res], error) {
+		res, err := make(a.Val, b.Val)
+		return graco.MergeEnvelope(a, b, res), err
+	}
+}
+
+// EnvelopeTriplet adapts a triplet maker of plain values to join envelopes. Metadata of all envelopes is merged into the result.
+func EnvelopeTriplet[A, B, C, Res any](make TripletMakerFunc[A, B, C, Res]) TripletMakerFunc[graco.Envelope[A], graco.Envelope[B], graco.Envelope[C], graco.Envelope[Res]] {
+	return func(a graco.Envelope[A], b graco.Envelope[B], c graco.Envelope[C]) (graco.Envelope[Res], error) {
+		res, err := make(a.Val, b.Val, c.Val)
+		return graco.MergeEnvelope(graco.MergeEnvelope(a, b, struct{}{}), c, res), err
+	}
+}
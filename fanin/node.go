@@ -3,12 +3,14 @@ package fanin
 import (
 	"context"
 	"errors"
+	"io"
 	"sync"
 
 	"github.com/itohio/graco"
 )
 
 type Node[T any] struct {
+	graco.NodeBase
 	name           string
 	synchroBuilder SynchronizerBuilder
 	inputs         []graco.SourceEdge[T]
@@ -25,10 +27,13 @@ func New[T any](name string, synchro SynchronizerBuilder) *Node[T] {
 }
 
 func (n *Node[T]) Close() error {
-	if n.output == nil {
-		return nil
-	}
-	return errors.Join(n.synchro.Close(), n.output.Close())
+	return n.CloseOnce(func() error {
+		var err error
+		if n.synchro != nil {
+			err = n.synchro.Close()
+		}
+		return errors.Join(err, n.CloseOutputs(n.output))
+	})
 }
 func (n *Node[T]) Name() string { return n.name }
 
@@ -49,7 +54,8 @@ func (n *Node[T]) Connect(in ...graco.SourceEdge[T]) (graco.SourceEdge[[]T], err
 	return n.output, err
 }
 
-func (n *Node[T]) Start(ctx context.Context) error {
+func (n *Node[T]) Start(pctx context.Context) error {
+	defer n.CloseOutputs(n.output)
 	if err := graco.IsEdgeValid(n.output); err != nil {
 		return err
 	}
@@ -62,9 +68,28 @@ func (n *Node[T]) Start(ctx context.Context) error {
 		return errors.New("synchro nil")
 	}
 
+	ctx, cancel := context.WithCancelCause(pctx)
+	defer cancel(nil)
+
+	// Errors releasing values that could not be sent.
+	var (
+		mu     sync.Mutex
+		relErr error
+	)
+	releaseAll := func(vals []any) {
+		for _, val := range vals {
+			if err := graco.Release(val); err != nil {
+				mu.Lock()
+				relErr = errors.Join(relErr, err)
+				mu.Unlock()
+			}
+		}
+	}
+
 	c := make(chan []any)
-	var globalErr error
+	sent := make(chan struct{})
 	go func() {
+		defer close(sent)
 		for res := range c {
 			arr := make([]T, len(res))
 			for i, in := range res {
@@ -75,8 +100,9 @@ func (n *Node[T]) Start(ctx context.Context) error {
 				arr[i] = val
 			}
 
-			if err := n.output.Send(ctx, arr); err != nil {
-				globalErr = err
+			if err := n.output.Send(pctx, arr); err != nil {
+				releaseAll(res)
+				cancel(err)
 				return
 			}
 		}
@@ -89,20 +115,32 @@ func (n *Node[T]) Start(ctx context.Context) error {
 			defer wg.Done()
 			for {
 				val, err := in.Recv(ctx)
+				if errors.Is(err, io.EOF) {
+					// Other inputs are drained until they end as well.
+					return
+				}
 				if err != nil {
-					if globalErr == nil {
-						globalErr = err
-					}
+					cancel(err)
 					return
 				}
 				res := n.synchro.Add(i, val)
-				if res != nil {
-					c <- res
+				if res == nil {
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					releaseAll(res)
+					return
+				case c <- res:
 				}
 			}
 		}(i, in)
 	}
 	wg.Wait()
 	close(c)
-	return globalErr
+	<-sent
+	if err := context.Cause(ctx); err != nil {
+		return errors.Join(err, relErr)
+	}
+	return io.EOF
 }
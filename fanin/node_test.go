@@ -0,0 +1,97 @@
+package fanin_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/itohio/graco"
+	"github.com/itohio/graco/fanin"
+	"github.com/itohio/graco/sink"
+	"github.com/itohio/graco/source"
+)
+
+type closer struct{ closed *int }
+
+func (c closer) Close() error {
+	*c.closed++
+	return nil
+}
+
+// holder holds every value until it is closed.
+type holder struct {
+	mu   sync.Mutex
+	vals []any
+}
+
+func (h *holder) Add(idx int, val any) []any {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.vals = append(h.vals, val)
+	return nil
+}
+
+func (h *holder) Close() error {
+	var err error
+	for _, val := range h.vals {
+		err = errors.Join(err, graco.Release(val))
+	}
+	return err
+}
+
+func TestNodeDrainsInputs(t *testing.T) {
+	closed := 0
+	produced := 0
+	a := source.New[closer]("a", source.Func[closer](func(ctx context.Context) (closer, error) {
+		return closer{}, io.EOF
+	}))
+	b := source.New[closer]("b", source.Func[closer](func(ctx context.Context) (closer, error) {
+		if produced == 3 {
+			return closer{}, io.EOF
+		}
+		// Input a ends first.
+		time.Sleep(10 * time.Millisecond)
+		produced++
+		return closer{&closed}, nil
+	}))
+	fi := fanin.New[closer]("fanin", func(items int) (fanin.Synchronizer, error) {
+		return &holder{}, nil
+	})
+	k := sink.NewFunc[[]closer]("k", sink.Func[[]closer](func(ctx context.Context, val []closer) error {
+		t.Error("unexpected result")
+		return nil
+	}))
+
+	oa, err := a.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ob, err := b.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, err := fi.Connect(oa, ob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.Connect(o)
+
+	g := graco.New()
+	g.AddNode(0, a, b, fi, k)
+	g.AddEdge(0, oa, ob, o)
+	if err := g.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if produced != 3 {
+		t.Errorf("produced %d values, want 3", produced)
+	}
+	if closed != 3 {
+		t.Errorf("released %d values, want 3", closed)
+	}
+}
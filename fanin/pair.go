@@ -7,9 +7,12 @@ import (
 	"github.com/itohio/graco"
 )
 
+// PairMakerFunc combines a pair of values. It takes ownership of the values only when it succeeds,
+// otherwise the node releases them.
 type PairMakerFunc[A, B, Res any] func(A, B) (Res, error)
 
 type PairNode[A, B, Res any] struct {
+	graco.NodeBase
 	name   string
 	a      graco.SourceEdge[A]
 	b      graco.SourceEdge[B]
@@ -26,10 +29,7 @@ func NewPair[A, B, Res any](name string, make PairMakerFunc[A, B, Res]) *PairNod
 }
 
 func (n *PairNode[A, B, Res]) Close() error {
-	if n.output == nil {
-		return nil
-	}
-	return n.output.Close()
+	return n.CloseOnce(func() error { return n.CloseOutputs(n.output) })
 }
 func (n *PairNode[A, B, Res]) Name() string { return n.name }
 
@@ -49,6 +49,7 @@ func (n *PairNode[A, B, Res]) Connect(a graco.SourceEdge[A], b graco.SourceEdge[
 }
 
 func (n *PairNode[A, B, Res]) Start(ctx context.Context) error {
+	defer n.CloseOutputs(n.output)
 	err := errors.Join(graco.IsEdgeValid(n.output),
 		graco.IsEdgeValid(n.a),
 		graco.IsEdgeValid(n.b),
@@ -64,16 +65,19 @@ func (n *PairNode[A, B, Res]) Start(ctx context.Context) error {
 		}
 		valb, err := n.b.Recv(ctx)
 		if err != nil {
-			return err
+			return errors.Join(err, graco.Release(vala))
 		}
 
 		res, err := n.make(vala, valb)
 		if err != nil {
-			return err
+			return errors.Join(err, graco.Release(vala), graco.Release(valb))
 		}
+		graco.Untrack(vala)
+		graco.Untrack(valb)
+		graco.Track(res)
 
 		if err := n.output.Send(ctx, res); err != nil {
-			return err
+			return errors.Join(err, graco.Release(res))
 		}
 	}
 }
@@ -3,9 +3,10 @@ package fanin
 import (
 	"container/ring"
 	"errors"
-	"io"
 	"sync"
 	"time"
+
+	"github.com/itohio/graco"
 )
 
 var (
@@ -57,11 +58,9 @@ func unlinkRing(r *ring.Ring, n int, last bool) (*ring.Ring, error) {
 		}
 		switch val := r.Value.(type) {
 		case tsItem:
-			if closer, ok := val.val.(io.Closer); ok {
-				err = errors.Join(err, closer.Close())
-			}
-		case io.Closer:
-			err = errors.Join(err, val.Close())
+			err = errors.Join(err, graco.Release(val.val))
+		default:
+			err = errors.Join(err, graco.Release(val))
 		}
 	}
 	return r.Unlink(N), err
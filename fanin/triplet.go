@@ -7,9 +7,12 @@ import (
 	"github.com/itohio/graco"
 )
 
+// TripletMakerFunc combines a triplet of values. It takes ownership of the values only when it succeeds,
+// otherwise the node releases them.
 type TripletMakerFunc[A, B, C, Res any] func(A, B, C) (Res, error)
 
 type TripletNode[A, B, C, Res any] struct {
+	graco.NodeBase
 	name   string
 	a      graco.SourceEdge[A]
 	b      graco.SourceEdge[B]
@@ -27,10 +30,7 @@ func NewTriplet[A, B, C, Res any](name string, make TripletMakerFunc[A, B, C, Re
 }
 
 func (n *TripletNode[A, B, C, Res]) Close() error {
-	if n.output == nil {
-		return nil
-	}
-	return n.output.Close()
+	return n.CloseOnce(func() error { return n.CloseOutputs(n.output) })
 }
 func (n *TripletNode[A, B, C, Res]) Name() string { return n.name }
 
@@ -50,6 +50,7 @@ func (n *TripletNode[A, B, C, Res]) Connect(a graco.SourceEdge[A], b graco.Sourc
 }
 
 func (n *TripletNode[A, B, C, Res]) Start(ctx context.Context) error {
+	defer n.CloseOutputs(n.output)
 	err := errors.Join(graco.IsEdgeValid(n.output),
 		graco.IsEdgeValid(n.a),
 		graco.IsEdgeValid(n.b),
@@ -66,20 +67,24 @@ func (n *TripletNode[A, B, C, Res]) Start(ctx context.Context) error {
 		}
 		valb, err := n.b.Recv(ctx)
 		if err != nil {
-			return err
+			return errors.Join(err, graco.Release(vala))
 		}
 		valc, err := n.c.Recv(ctx)
 		if err != nil {
-			return err
+			return errors.Join(err, graco.Release(vala), graco.Release(valb))
 		}
 
 		res, err := n.make(vala, valb, valc)
 		if err != nil {
-			return err
+			return errors.Join(err, graco.Release(vala), graco.Release(valb), graco.Release(valc))
 		}
+		graco.Untrack(vala)
+		graco.Untrack(valb)
+		graco.Untrack(valc)
+		graco.Track(res)
 
 		if err := n.output.Send(ctx, res); err != nil {
-			return err
+			return errors.Join(err, graco.Release(res))
 		}
 	}
 }
@@ -0,0 +1,117 @@
+package fanout
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/itohio/graco"
+)
+
+// BatchNode is a batch-aware variant of Node. It receives values in batches of up to max values
+// and sends each batch to every output at once.
+type BatchNode[T any] struct {
+	graco.NodeBase
+	name    string
+	input   graco.SourceEdge[T]
+	outputs []graco.SourceEdge[T]
+	max     int
+	linger  time.Duration
+}
+
+// NewBatch creates a BatchNode with N outputs that collects up to max values or waits at most linger for a batch to fill.
+func NewBatch[T any](name string, N, max int, linger time.Duration) *BatchNode[T] {
+	if max < 1 {
+		max = 1
+	}
+	res := &BatchNode[T]{
+		name:    name,
+		outputs: make([]graco.SourceEdge[T], N),
+		max:     max,
+		linger:  linger,
+	}
+	return res
+}
+
+func (n *BatchNode[T]) Close() error {
+	return n.CloseOnce(n.closeOutputs)
+}
+
+func (n *BatchNode[T]) closeOutputs() error {
+	edges := make([]graco.Edge, len(n.outputs))
+	for i, o := range n.outputs {
+		if o != nil {
+			edges[i] = o
+		}
+	}
+	return n.CloseOutputs(edges...)
+}
+func (n *BatchNode[T]) Name() string { return n.name }
+
+func (n *BatchNode[T]) Connect(in graco.SourceEdge[T]) ([]graco.SourceEdge[T], error) {
+	n.input = in
+	err := in.Connect(n)
+	if err != nil {
+		return nil, err
+	}
+	for i := range n.outputs {
+		n.outputs[i], err = graco.NewSourceEdge[T]("o", n, n.max, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return n.outputs, nil
+}
+
+func (n *BatchNode[T]) Start(ctx context.Context) error {
+	defer n.closeOutputs()
+	if err := graco.IsEdgeValid(n.input); err != nil {
+		return err
+	}
+	for _, out := range n.outputs {
+		if err := graco.IsEdgeValid(out); err != nil {
+			return err
+		}
+	}
+
+	for {
+		vals, err := graco.RecvBatch(ctx, n.input, n.max, n.linger)
+		if err != nil {
+			return err
+		}
+
+		if err := shareBatch(ctx, vals, n.outputs); err != nil {
+			return err
+		}
+	}
+}
+
+// cloned reports whether cloneBatch clones the value.
+func cloned[T any](val T) bool {
+	_, ok := any(val).(Cloner[T])
+	_, shared := any(val).(graco.Retainer)
+	return ok && !shared
+}
+
+// cloneBatch clones values implementing Cloner. Other values, including reference counted ones, are copied as is.
+func cloneBatch[T any](vals []T) ([]T, error) {
+	res := make([]T, len(vals))
+	for i, val := range vals {
+		if !cloned(val) {
+			res[i] = val
+			continue
+		}
+		v, err := any(val).(Cloner[T]).Clone()
+		if err != nil {
+			for j, val := range vals[:i] {
+				if cloned(val) {
+					err = errors.Join(err, graco.Release(res[j]))
+				}
+			}
+			return nil, err
+		}
+		graco.Track(v)
+		res[i] = v
+	}
+	return res, nil
+}
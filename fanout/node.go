@@ -7,11 +7,16 @@ import (
 	"github.com/itohio/graco"
 )
 
+var (
+	_ Cloner[graco.Envelope[int]] = graco.Envelope[int]{}
+)
+
 type Cloner[T any] interface {
 	Clone() (T, error)
 }
 
 type Node[T any] struct {
+	graco.NodeBase
 	name    string
 	input   graco.SourceEdge[T]
 	outputs []graco.SourceEdge[T]
@@ -26,14 +31,17 @@ func New[T any](name string, N int) *Node[T] {
 }
 
 func (n *Node[T]) Close() error {
-	if n.outputs == nil {
-		return nil
-	}
-	es := make([]error, len(n.outputs))
+	return n.CloseOnce(n.closeOutputs)
+}
+
+func (n *Node[T]) closeOutputs() error {
+	edges := make([]graco.Edge, len(n.outputs))
 	for i, o := range n.outputs {
-		es[i] = o.Close()
+		if o != nil {
+			edges[i] = o
+		}
 	}
-	return errors.Join(es...)
+	return n.CloseOutputs(edges...)
 }
 func (n *Node[T]) Name() string { return n.name }
 
@@ -47,6 +55,7 @@ func (n *Node[T]) Connect(in graco.SourceEdge[T]) ([]graco.SourceEdge[T], error)
 }
 
 func (n *Node[T]) Start(ctx context.Context) error {
+	defer n.closeOutputs()
 	if err := graco.IsEdgeValid(n.input); err != nil {
 		return err
 	}
@@ -62,19 +71,43 @@ func (n *Node[T]) Start(ctx context.Context) error {
 			return err
 		}
 
-		cloner, ok := any(val).(Cloner[T])
+		if r, ok := any(val).(graco.Retainer); ok {
+			if err := share(ctx, r, val, n.outputs); err != nil {
+				return err
+			}
+			continue
+		}
 
-		for _, o := range n.outputs {
-			v := val
-			if ok {
-				v, err = cloner.Clone()
-				if err != nil {
-					return err
-				}
+		if cloner, ok := any(val).(Cloner[T]); ok {
+			if err := clone(ctx, cloner, val, n.outputs); err != nil {
+				return err
 			}
-			if err := o.Send(ctx, v); err != nil {
+			continue
+		}
+
+		for i, o := range n.outputs {
+			if err := o.Send(ctx, val); err != nil {
+				if i == 0 {
+					// No output received the value.
+					err = errors.Join(err, graco.Release(val))
+				}
 				return err
 			}
 		}
 	}
 }
+
+// clone sends a clone of the value to every output and releases the original.
+func clone[T any](ctx context.Context, cloner Cloner[T], val T, outputs []graco.SourceEdge[T]) error {
+	for _, o := range outputs {
+		v, err := cloner.Clone()
+		if err != nil {
+			return errors.Join(err, graco.Release(val))
+		}
+		graco.Track(v)
+		if err := o.Send(ctx, v); err != nil {
+			return errors.Join(err, graco.Release(v), graco.Release(val))
+		}
+	}
+	return graco.Release(val)
+}
@@ -0,0 +1,54 @@
+package fanout_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/itohio/graco"
+	"github.com/itohio/graco/fanout"
+	"github.com/itohio/graco/sink"
+	"github.com/itohio/graco/source"
+)
+
+func TestNodeClonesEnvelopes(t *testing.T) {
+	type E = graco.Envelope[*graco.Shared[int]]
+
+	released := 0
+	i := 0
+	src := source.New[E]("src", source.Func[E](func(ctx context.Context) (E, error) {
+		i++
+		if i > 3 {
+			return E{}, io.EOF
+		}
+		return E{Val: graco.NewShared(i, func(int) { released++ })}, nil
+	}))
+	fo := fanout.New[E]("fanout", 2)
+	consume := func(ctx context.Context, val E) error { return val.Close() }
+	k1 := sink.NewFunc[E]("k1", sink.Func[E](consume))
+	k2 := sink.NewFunc[E]("k2", sink.Func[E](consume))
+
+	o, err := src.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outs, err := fo.Connect(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k1.Connect(outs[0])
+	k2.Connect(outs[1])
+
+	g := graco.New()
+	g.AddNode(0, src, fo, k1, k2)
+	g.AddEdge(0, o, outs[0], outs[1])
+	if err := g.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if released != 3 {
+		t.Errorf("released %d values, want 3", released)
+	}
+}
@@ -0,0 +1,78 @@
+package fanout
+
+import (
+	"context"
+	"errors"
+
+	"github.com/itohio/graco"
+)
+
+// share sends a reference counted value to every output without copying.
+// A reference is retained for each output and the reference of the input is released.
+func share[T any](ctx context.Context, r graco.Retainer, val T, outputs []graco.SourceEdge[T]) error {
+	for range outputs {
+		r.Retain()
+	}
+	for i, o := range outputs {
+		if err := o.Send(ctx, val); err != nil {
+			return errors.Join(err, release(r, len(outputs)-i+1))
+		}
+	}
+	return r.Close()
+}
+
+// shareBatch sends a batch of values to every output. Reference counted values are retained for each output
+// instead of being cloned, and references of the input as well as cloned originals are released.
+func shareBatch[T any](ctx context.Context, vals []T, outputs []graco.SourceEdge[T]) error {
+	for _, val := range vals {
+		if r, ok := any(val).(graco.Retainer); ok {
+			for range outputs {
+				r.Retain()
+			}
+		}
+	}
+	for i, o := range outputs {
+		var sent int
+		batch, err := cloneBatch(vals)
+		if err == nil {
+			sent, err = graco.SendBatch(ctx, o, batch)
+		}
+		if err != nil {
+			// References of the remaining outputs and of the input are released,
+			// as well as the reference of this output for values that were not sent.
+			for j, val := range vals {
+				if r, ok := any(val).(graco.Retainer); ok {
+					n := len(outputs) - i
+					if j >= sent {
+						n++
+					}
+					err = errors.Join(err, release(r, n))
+				} else if cloned(val) {
+					if batch != nil && j >= sent {
+						err = errors.Join(err, graco.Release(batch[j]))
+					}
+					err = errors.Join(err, graco.Release(val))
+				}
+			}
+			return err
+		}
+	}
+	var err error
+	for _, val := range vals {
+		if r, ok := any(val).(graco.Retainer); ok {
+			err = errors.Join(err, r.Close())
+		} else if cloned(val) {
+			err = errors.Join(err, graco.Release(val))
+		}
+	}
+	return err
+}
+
+// release releases n references.
+func release(r graco.Retainer, n int) error {
+	var err error
+	for i := 0; i < n; i++ {
+		err = errors.Join(err, r.Close())
+	}
+	return err
+}
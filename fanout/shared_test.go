@@ -0,0 +1,68 @@
+package fanout
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/itohio/graco"
+)
+
+type nopNode struct{}
+
+func (nopNode) Close() error                    { return nil }
+func (nopNode) Name() string                    { return "nop" }
+func (nopNode) Start(ctx context.Context) error { return nil }
+
+func TestShareBatchPartialSend(t *testing.T) {
+	ctx := context.Background()
+	out0, err := graco.NewSourceEdge[*graco.Shared[int]]("o0", nopNode{}, 8, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out1, err := graco.NewSourceEdge[*graco.Shared[int]]("o1", nopNode{}, 4, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out0.Connect(nopNode{})
+	out1.Connect(nopNode{})
+	for i := 0; i < 3; i++ {
+		if err := out1.Send(ctx, graco.NewShared(-1, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	vals := []*graco.Shared[int]{graco.NewShared(0, nil), graco.NewShared(1, nil), graco.NewShared(2, nil)}
+	done := make(chan error)
+	go func() {
+		done <- shareBatch(ctx, vals, []graco.SourceEdge[*graco.Shared[int]]{out0, out1})
+	}()
+
+	// The first value fills the second output, after which the batch is sent partially.
+	for out1.Len() < 4 {
+		time.Sleep(time.Millisecond)
+	}
+	if err := out1.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; !errors.Is(err, io.EOF) {
+		t.Fatalf("got %v, want io.EOF", err)
+	}
+
+	for range vals {
+		v, err := out0.Recv(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := v.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, v := range vals {
+		if refs := v.Refs(); refs != 0 {
+			t.Errorf("value %d: %d references left", i, refs)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package fanout
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/itohio/graco"
+)
+
+// Overflow is the policy applied when a subscriber buffer is full.
+type Overflow int
+
+const (
+	// Block waits until the subscriber has room, slowing down all subscribers.
+	Block Overflow = iota
+	// DropNewest discards the value that does not fit.
+	DropNewest
+	// DropOldest discards the oldest buffered value to make room.
+	DropOldest
+)
+
+// Subscription is the input edge of a Topic subscriber.
+type Subscription[T any] struct {
+	*graco.ChannelSourceEdge[T]
+	overflow Overflow
+	done     chan struct{}
+	once     sync.Once
+}
+
+func (s *Subscription[T]) cancel() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// Topic is a node that publishes its input to a dynamic set of subscribers.
+// Subscribers may subscribe and unsubscribe at any time, each getting its own buffer and overflow policy.
+// Reference counted values implementing graco.Retainer are shared by subscribers, values implementing Cloner are cloned for each subscriber. Values dropped due to overflow are released.
+type Topic[T any] struct {
+	graco.NodeBase
+	name  string
+	input graco.SourceEdge[T]
+
+	mu   sync.RWMutex
+	subs []*Subscription[T]
+}
+
+func NewTopic[T any](name string) *Topic[T] {
+	res := &Topic[T]{
+		name: name,
+	}
+	return res
+}
+
+// Close unsubscribes all subscribers.
+func (n *Topic[T]) Close() error {
+	return n.CloseOnce(n.unsubscribeAll)
+}
+
+func (n *Topic[T]) unsubscribeAll() error {
+	n.mu.Lock()
+	subs := n.subs
+	n.subs = nil
+	n.mu.Unlock()
+
+	es := make([]error, len(subs))
+	for i, s := range subs {
+		s.cancel()
+		es[i] = s.Close()
+	}
+	return errors.Join(es...)
+}
+func (n *Topic[T]) Name() string { return n.name }
+
+func (n *Topic[T]) Connect(in graco.SourceEdge[T]) error {
+	n.input = in
+	return in.Connect(n)
+}
+
+// Subscribe creates a new subscription with a buffer of cap values.
+// The returned edge must be connected to the consuming node.
+func (n *Topic[T]) Subscribe(cap int, overflow Overflow) (*Subscription[T], error) {
+	if cap < 1 && overflow != Block {
+		cap = 1
+	}
+	e, err := graco.NewSourceEdge[T]("s", n, cap, false)
+	if err != nil {
+		return nil, err
+	}
+	s := &Subscription[T]{
+		ChannelSourceEdge: e,
+		overflow:          overflow,
+		done:              make(chan struct{}),
+	}
+
+	n.mu.Lock()
+	n.subs = append(n.subs, s)
+	n.mu.Unlock()
+	return s, nil
+}
+
+// Unsubscribe removes the subscription and closes its edge. The consumer receives io.EOF after draining the buffer.
+func (n *Topic[T]) Unsubscribe(s *Subscription[T]) error {
+	// Cancel first so that a publisher blocked on this subscriber releases the lock.
+	s.cancel()
+
+	n.mu.Lock()
+	found := false
+	for i, sub := range n.subs {
+		if sub == s {
+			n.subs = append(n.subs[:i], n.subs[i+1:]...)
+			found = true
+			break
+		}
+	}
+	n.mu.Unlock()
+
+	if !found {
+		return errors.New("not subscribed")
+	}
+	return s.Close()
+}
+
+// Subscribers returns the number of active subscriptions.
+func (n *Topic[T]) Subscribers() int {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return len(n.subs)
+}
+
+// Start publishes input values until the input is exhausted or the context is canceled.
+// All subscribers are unsubscribed when Start returns.
+func (n *Topic[T]) Start(ctx context.Context) error {
+	defer n.unsubscribeAll()
+	if err := graco.IsEdgeValid(n.input); err != nil {
+		return err
+	}
+
+	for {
+		val, err := n.input.Recv(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := n.publish(ctx, val); err != nil {
+			return err
+		}
+	}
+}
+
+func (n *Topic[T]) publish(ctx context.Context, val T) error {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if r, ok := any(val).(graco.Retainer); ok {
+		for range n.subs {
+			r.Retain()
+		}
+		for i, s := range n.subs {
+			if err := s.publish(ctx, val); err != nil {
+				// The reference of this subscriber was released by it.
+				return errors.Join(err, release(r, len(n.subs)-i))
+			}
+		}
+		return r.Close()
+	}
+
+	if cloner, ok := any(val).(Cloner[T]); ok {
+		for _, s := range n.subs {
+			v, err := cloner.Clone()
+			if err != nil {
+				return errors.Join(err, graco.Release(val))
+			}
+			graco.Track(v)
+			if err := s.publish(ctx, v); err != nil {
+				return errors.Join(err, graco.Release(val))
+			}
+		}
+		return graco.Release(val)
+	}
+
+	if len(n.subs) == 0 {
+		return graco.Release(val)
+	}
+	for _, s := range n.subs {
+		if err := s.publish(ctx, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publish delivers the value to the subscriber or releases it if it is dropped or cannot be delivered.
+func (s *Subscription[T]) publish(ctx context.Context, val T) error {
+	ch := s.C()
+	gone := s.Disconnected()
+	for {
+		select {
+		case <-s.done:
+			return graco.Release(val)
+		case <-gone:
+			// The subscriber finished.
+			return graco.Release(val)
+		case ch <- val:
+			return nil
+		default:
+		}
+
+		switch s.overflow {
+		case DropNewest:
+			return graco.Release(val)
+		case DropOldest:
+			select {
+			case old := <-ch:
+				if err := graco.Release(old); err != nil {
+					return errors.Join(err, graco.Release(val))
+				}
+			default:
+			}
+		default:
+			select {
+			case <-ctx.Done():
+				return errors.Join(context.Cause(ctx), graco.Release(val))
+			case <-s.done:
+				return graco.Release(val)
+			case <-gone:
+				return graco.Release(val)
+			case ch <- val:
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package fanout_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/itohio/graco"
+	"github.com/itohio/graco/fanout"
+	"github.com/itohio/graco/sink"
+	"github.com/itohio/graco/source"
+)
+
+type closer struct{ closed *int }
+
+func (c closer) Close() error {
+	*c.closed++
+	return nil
+}
+
+func TestTopicReleasesUnsubscribed(t *testing.T) {
+	closed := 0
+	i := 0
+	src := source.New[closer]("src", source.Func[closer](func(ctx context.Context) (closer, error) {
+		i++
+		if i > 3 {
+			return closer{}, io.EOF
+		}
+		return closer{&closed}, nil
+	}))
+	topic := fanout.NewTopic[closer]("topic")
+
+	o, err := src.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := topic.Connect(o); err != nil {
+		t.Fatal(err)
+	}
+
+	g := graco.New()
+	g.AddNode(0, src, topic)
+	g.AddEdge(0, o)
+	if err := g.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if closed != 3 {
+		t.Errorf("released %d values, want 3", closed)
+	}
+}
+
+func TestTopicClonesEnvelopes(t *testing.T) {
+	type E = graco.Envelope[*graco.Shared[int]]
+
+	released := 0
+	i := 0
+	src := source.New[E]("src", source.Func[E](func(ctx context.Context) (E, error) {
+		i++
+		if i > 3 {
+			return E{}, io.EOF
+		}
+		return E{Val: graco.NewShared(i, func(int) { released++ })}, nil
+	}))
+	topic := fanout.NewTopic[E]("topic")
+	consume := func(ctx context.Context, val E) error { return val.Close() }
+	k1 := sink.NewFunc[E]("k1", sink.Func[E](consume))
+	k2 := sink.NewFunc[E]("k2", sink.Func[E](consume))
+
+	o, err := src.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := topic.Connect(o); err != nil {
+		t.Fatal(err)
+	}
+	s1, err := topic.Subscribe(1, fanout.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := topic.Subscribe(1, fanout.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k1.Connect(s1)
+	k2.Connect(s2)
+
+	g := graco.New()
+	g.AddNode(0, src, topic, k1, k2)
+	g.AddEdge(0, o, s1, s2)
+	if err := g.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if released != 3 {
+		t.Errorf("released %d values, want 3", released)
+	}
+}
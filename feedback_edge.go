@@ -0,0 +1,49 @@
+package graco
+
+import "errors"
+
+var (
+	_ SourceEdge[int] = (*ChannelFeedbackEdge[int])(nil)
+	_ FeedbackEdge    = (*ChannelFeedbackEdge[int])(nil)
+)
+
+// ChannelFeedbackEdge is a ChannelSourceEdge that closes a loop in the graph.
+// It is primed with initial values so that the loop can make progress, e.g. the initial state of a controller.
+type ChannelFeedbackEdge[T any] struct {
+	*ChannelSourceEdge[T]
+}
+
+// NewFeedbackEdge creates a ChannelFeedbackEdge[T] instance primed with initial values.
+// cap: Capacity of the underlying channel. It is increased to fit the initial values if needed.
+func NewFeedbackEdge[T any](name string, src Node, cap int, initial ...T) (*ChannelFeedbackEdge[T], error) {
+	if cap < len(initial) {
+		cap = len(initial)
+	}
+	e, err := NewSourceEdge[T](name, src, cap, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, val := range initial {
+		e.ch <- val
+	}
+	res := &ChannelFeedbackEdge[T]{
+		ChannelSourceEdge: e,
+	}
+	return res, nil
+}
+
+// Break disconnects the edge, see ChannelSourceEdge.Disconnect.
+func (e *ChannelFeedbackEdge[T]) Break() error {
+	return e.Disconnect()
+}
+
+// Close closes the underlying channel and discards buffered values if the loop is broken.
+func (e *ChannelFeedbackEdge[T]) Close() error {
+	err := e.ChannelSourceEdge.Close()
+	select {
+	case <-e.gone:
+		return errors.Join(err, e.Drain())
+	default:
+	}
+	return err
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 )
 
 type Graph interface {
+	io.Closer
 	AddNode(seq int, n ...Node) error
 	AddEdge(seq int, e ...Edge) error
 	Start(ctx context.Context) error
@@ -35,8 +38,10 @@ func (n withStartSequenceSlice[T]) Count(c func(T) bool) int {
 }
 
 type ConcurrentGraph struct {
-	nodes withStartSequenceSlice[Node]
-	edges withStartSequenceSlice[Edge]
+	nodes     withStartSequenceSlice[Node]
+	edges     withStartSequenceSlice[Edge]
+	closeOnce sync.Once
+	closeErr  error
 }
 
 func New() *ConcurrentGraph {
@@ -63,14 +68,35 @@ func (g *ConcurrentGraph) AddEdge(seq int, e ...Edge) error {
 	return nil
 }
 
+// Start validates the graph and runs all nodes and starter edges until the graph completes or one of them fails.
+//
+// A node finishing with io.EOF, ErrStop, context.Canceled or no error finishes cleanly and does not stop other nodes.
+// Edges it consumes are disconnected, or broken if they are feedback edges, so that nodes producing them finish too.
+// Nodes whose outputs do not feed any node of the graph are terminal, e.g. sinks. Once all terminal nodes finish,
+// remaining nodes and starter edges are canceled. A graph without terminal nodes completes when any node finishes.
+// A node or edge failing with any other error cancels the whole graph.
 func (g *ConcurrentGraph) Start(pctx context.Context) error {
 	sort.Sort(g.edges)
 	sort.Sort(g.nodes)
+	if err := g.Validate(); err != nil {
+		return err
+	}
+	inputs := make(map[Node][]Edge)
+	for _, e := range g.edges {
+		_, dst := e.val.Nodes()
+		inputs[dst] = append(inputs[dst], e.val)
+	}
+	terminals := g.terminals()
+	var pending, running atomic.Int32
+	pending.Store(int32(len(terminals)))
+	running.Store(int32(len(g.nodes)))
+
 	var wg sync.WaitGroup
 	ctx, cancel := context.WithCancelCause(pctx)
 	defer cancel(nil)
 
-	errarr := make([]error, len(g.nodes)+g.edges.Count(func(e Edge) bool {
+	// Every node may fail and fail to disconnect its inputs.
+	errarr := make([]error, 2*len(g.nodes)+g.edges.Count(func(e Edge) bool {
 		_, ok := e.(EdgeStarter)
 		return ok
 	}))
@@ -84,11 +110,11 @@ func (g *ConcurrentGraph) Start(pctx context.Context) error {
 		wg.Add(1)
 		go func(e EdgeStarter) {
 			err := e.Start(ctx)
-			if errors.Is(err, context.Canceled) {
+			if clean(err) {
 				err = nil
 			}
-			cancel(err)
 			if err != nil {
+				cancel(err)
 				errarr[errnum.Add(1)-1] = fmt.Errorf("edge '%s' failed: %w", e.Name(), err)
 			}
 			wg.Done()
@@ -99,12 +125,25 @@ func (g *ConcurrentGraph) Start(pctx context.Context) error {
 		wg.Add(1)
 		go func(n Node) {
 			err := n.Start(ctx)
-			if errors.Is(err, context.Canceled) {
+			if clean(err) {
 				err = nil
 			}
-			cancel(err)
-			if err != nil {
+			if derr := disconnect(inputs[n]); derr != nil {
+				cancel(derr)
+				errarr[errnum.Add(1)-1] = fmt.Errorf("node '%s' disconnect failed: %w", n.Name(), derr)
+			}
+			switch {
+			case err != nil:
+				cancel(err)
 				errarr[errnum.Add(1)-1] = fmt.Errorf("node '%s' failed: %w", n.Name(), err)
+			case len(terminals) == 0:
+				cancel(nil)
+			case terminals[n] && pending.Add(-1) == 0:
+				cancel(nil)
+			}
+			if running.Add(-1) == 0 {
+				// Stop starter edges once all nodes have finished.
+				cancel(nil)
 			}
 			wg.Done()
 		}(n.val)
@@ -112,3 +151,249 @@ func (g *ConcurrentGraph) Start(pctx context.Context) error {
 	wg.Wait()
 	return errors.Join(errarr...)
 }
+
+// Close releases every node and edge after Start returns.
+// Nodes are closed in topological order so that producers are closed before their consumers.
+// Edges are closed afterwards, which is a no-op for edges already closed by their source nodes,
+// and values left in edge buffers are released.
+func (g *ConcurrentGraph) Close() error {
+	g.closeOnce.Do(func() {
+		var errs []error
+		for _, n := range g.closeOrder() {
+			if err := n.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("node '%s' close failed: %w", n.Name(), err))
+			}
+		}
+		for _, e := range g.edges {
+			if err := e.val.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("edge '%s' close failed: %w", e.val.Name(), err))
+			}
+			if d, ok := e.val.(Drainer); ok {
+				if err := d.Drain(); err != nil {
+					errs = append(errs, fmt.Errorf("edge '%s' drain failed: %w", e.val.Name(), err))
+				}
+			}
+		}
+		g.closeErr = errors.Join(errs...)
+	})
+	return g.closeErr
+}
+
+// clean reports whether an error means that a node or an edge finished without failing.
+// A joined error is clean only if every error it joins is clean, e.g. ErrStop joined with a failed release is not.
+func clean(err error) bool {
+	if err == nil {
+		return true
+	}
+	if errs, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range errs.Unwrap() {
+			if !clean(err) {
+				return false
+			}
+		}
+		return true
+	}
+	if err := errors.Unwrap(err); err != nil {
+		return clean(err)
+	}
+	return errors.Is(err, context.Canceled) || errors.Is(err, io.EOF) || errors.Is(err, ErrStop)
+}
+
+// disconnect releases the input edges of a finished node so that their source nodes finish too.
+func disconnect(edges []Edge) error {
+	var err error
+	for _, e := range edges {
+		if fe, ok := e.(FeedbackEdge); ok {
+			err = errors.Join(err, fe.Break())
+			continue
+		}
+		if d, ok := e.(Disconnecter); ok {
+			err = errors.Join(err, d.Disconnect())
+		}
+	}
+	return err
+}
+
+// terminals returns nodes whose outputs do not feed any node of the graph.
+func (g *ConcurrentGraph) terminals() map[Node]bool {
+	nodes := make(map[Node]bool, len(g.nodes))
+	for _, n := range g.nodes {
+		nodes[n.val] = true
+	}
+	feeding := make(map[Node]bool)
+	for _, e := range g.edges {
+		_, dst := e.val.Nodes()
+		if !nodes[dst] {
+			continue
+		}
+		for _, p := range producers(e.val) {
+			feeding[p] = true
+		}
+		if r, ok := e.val.(Replier); ok && r.Replies() {
+			feeding[dst] = true
+		}
+	}
+	res := make(map[Node]bool)
+	for n := range nodes {
+		if !feeding[n] {
+			res[n] = true
+		}
+	}
+	return res
+}
+
+// producers returns source nodes of an edge.
+func producers(e Edge) []Node {
+	if mp, ok := e.(MultiProducerEdge); ok {
+		return mp.Producers()
+	}
+	src, _ := e.Nodes()
+	if src == nil {
+		return nil
+	}
+	return []Node{src}
+}
+
+// Validate checks that every loop in the graph contains a feedback edge.
+func (g *ConcurrentGraph) Validate() error {
+	var errs []error
+	for _, loop := range g.components(false) {
+		names := make([]string, len(loop))
+		for i, n := range loop {
+			names[i] = n.Name()
+		}
+		errs = append(errs, fmt.Errorf("loop without feedback edge: %s", strings.Join(names, ", ")))
+	}
+	return errors.Join(errs...)
+}
+
+// Loops returns groups of nodes that form loops in the graph, including loops closed by feedback edges.
+func (g *ConcurrentGraph) Loops() [][]Node {
+	return g.components(true)
+}
+
+// adjacency indexes nodes and returns edges between them as adjacency lists.
+func (g *ConcurrentGraph) adjacency(feedback bool) [][]int {
+	index := make(map[Node]int, len(g.nodes))
+	for i, n := range g.nodes {
+		index[n.val] = i
+	}
+	next := make([][]int, len(g.nodes))
+	for _, e := range g.edges {
+		if _, ok := e.val.(FeedbackEdge); ok && !feedback {
+			continue
+		}
+		_, dst := e.val.Nodes()
+		j, ok := index[dst]
+		if !ok {
+			continue
+		}
+		for _, src := range producers(e.val) {
+			if i, ok := index[src]; ok {
+				next[i] = append(next[i], j)
+			}
+		}
+	}
+	return next
+}
+
+// components finds strongly connected components that form loops using Tarjan's algorithm.
+func (g *ConcurrentGraph) components(feedback bool) [][]Node {
+	next := g.adjacency(feedback)
+	var (
+		res     [][]Node
+		counter int
+		stack   []int
+		index   = make([]int, len(next))
+		low     = make([]int, len(next))
+		onStack = make([]bool, len(next))
+		visit   func(int)
+	)
+	for i := range index {
+		index[i] = -1
+	}
+
+	visit = func(i int) {
+		index[i] = counter
+		low[i] = counter
+		counter++
+		stack = append(stack, i)
+		onStack[i] = true
+
+		selfLoop := false
+		for _, j := range next[i] {
+			if j == i {
+				selfLoop = true
+			}
+			if index[j] < 0 {
+				visit(j)
+				if low[j] < low[i] {
+					low[i] = low[j]
+				}
+			} else if onStack[j] && index[j] < low[i] {
+				low[i] = index[j]
+			}
+		}
+		if low[i] != index[i] {
+			return
+		}
+
+		var component []Node
+		for {
+			j := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[j] = false
+			component = append(component, g.nodes[j].val)
+			if j == i {
+				break
+			}
+		}
+		if len(component) > 1 || selfLoop {
+			res = append(res, component)
+		}
+	}
+	for i := range next {
+		if index[i] < 0 {
+			visit(i)
+		}
+	}
+	return res
+}
+
+// closeOrder sorts nodes topologically ignoring feedback edges. Nodes that cannot be ordered keep registration order.
+func (g *ConcurrentGraph) closeOrder() []Node {
+	next := g.adjacency(false)
+	indegree := make([]int, len(next))
+	for _, js := range next {
+		for _, j := range js {
+			indegree[j]++
+		}
+	}
+
+	res := make([]Node, 0, len(g.nodes))
+	visited := make([]bool, len(g.nodes))
+	queue := make([]int, 0, len(g.nodes))
+	for i := range g.nodes {
+		if indegree[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		visited[i] = true
+		res = append(res, g.nodes[i].val)
+		for _, j := range next[i] {
+			indegree[j]--
+			if indegree[j] == 0 {
+				queue = append(queue, j)
+			}
+		}
+	}
+	for i, n := range g.nodes {
+		if !visited[i] {
+			res = append(res, n.val)
+		}
+	}
+	return res
+}
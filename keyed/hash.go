@@ -0,0 +1,78 @@
+package keyed
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+)
+
+// Hash returns a stable 64-bit hash of a key. Strings, booleans, integers and floats are hashed directly,
+// other keys are hashed by their fmt representation, which is slower.
+func Hash[K comparable](key K) uint64 {
+	var v uint64
+	switch k := any(key).(type) {
+	case string:
+		return hashString(k)
+	case bool:
+		if k {
+			v = 1
+		}
+	case int:
+		v = uint64(k)
+	case int8:
+		v = uint64(k)
+	case int16:
+		v = uint64(k)
+	case int32:
+		v = uint64(k)
+	case int64:
+		v = uint64(k)
+	case uint:
+		v = uint64(k)
+	case uint8:
+		v = uint64(k)
+	case uint16:
+		v = uint64(k)
+	case uint32:
+		v = uint64(k)
+	case uint64:
+		v = k
+	case uintptr:
+		v = uint64(k)
+	case float32:
+		v = uint64(math.Float32bits(k))
+	case float64:
+		v = math.Float64bits(k)
+	default:
+		return hashString(fmt.Sprintf("%#v", k))
+	}
+	return mix(v)
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// mix spreads the bits of an integer key (splitmix64 finalizer).
+func mix(v uint64) uint64 {
+	v ^= v >> 30
+	v *= 0xbf58476d1ce4e5b9
+	v ^= v >> 27
+	v *= 0x94d049bb133111eb
+	v ^= v >> 31
+	return v
+}
+
+// Partition maps a hash to one of n partitions using jump consistent hashing,
+// which moves only a minimal fraction of keys when the number of partitions changes.
+func Partition(hash uint64, n int) int {
+	var b, j int64 = -1, 0
+	for j < int64(n) {
+		b = j
+		hash = hash*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((hash>>33)+1)))
+	}
+	return int(b)
+}
@@ -0,0 +1,88 @@
+// Package keyed provides operators for keyed streams.
+//
+// KeyBy routes values to partitions by key, so that all values with the same key follow the same path in order.
+// Node processes keyed values on a worker per partition, each owning a Store of per-key state.
+package keyed
+
+import (
+	"context"
+	"errors"
+
+	"github.com/itohio/graco"
+)
+
+// KeyBy is a node that routes every input value to one of its outputs chosen by consistent hashing of the value key.
+type KeyBy[T any, K comparable] struct {
+	graco.NodeBase
+	name    string
+	key     func(T) K
+	input   graco.SourceEdge[T]
+	outputs []graco.SourceEdge[T]
+}
+
+// NewKeyBy creates a KeyBy node with N outputs. key extracts the key of a value.
+func NewKeyBy[T any, K comparable](name string, N int, key func(T) K) *KeyBy[T, K] {
+	res := &KeyBy[T, K]{
+		name:    name,
+		key:     key,
+		outputs: make([]graco.SourceEdge[T], N),
+	}
+	return res
+}
+
+func (n *KeyBy[T, K]) Close() error {
+	return n.CloseOnce(n.closeOutputs)
+}
+
+func (n *KeyBy[T, K]) closeOutputs() error {
+	edges := make([]graco.Edge, len(n.outputs))
+	for i, o := range n.outputs {
+		if o != nil {
+			edges[i] = o
+		}
+	}
+	return n.CloseOutputs(edges...)
+}
+func (n *KeyBy[T, K]) Name() string { return n.name }
+
+func (n *KeyBy[T, K]) Connect(in graco.SourceEdge[T]) ([]graco.SourceEdge[T], error) {
+	n.input = in
+	err := in.Connect(n)
+	if err != nil {
+		return nil, err
+	}
+	for i := range n.outputs {
+		n.outputs[i], err = graco.NewSourceEdge[T]("o", n, 1, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return n.outputs, nil
+}
+
+func (n *KeyBy[T, K]) Start(ctx context.Context) error {
+	defer n.closeOutputs()
+	if err := graco.IsEdgeValid(n.input); err != nil {
+		return err
+	}
+	for _, out := range n.outputs {
+		if err := graco.IsEdgeValid(out); err != nil {
+			return err
+		}
+	}
+	if len(n.outputs) == 0 {
+		return errors.New("no outputs")
+	}
+
+	for {
+		val, err := n.input.Recv(ctx)
+		if err != nil {
+			return err
+		}
+
+		out := n.outputs[Partition(Hash(n.key(val)), len(n.outputs))]
+		if err := out.Send(ctx, val); err != nil {
+			return errors.Join(err, graco.Release(val))
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package keyed
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/itohio/graco"
+	"github.com/itohio/graco/processor"
+)
+
+var (
+	_ ProcessCloser[string, int, int, float32] = processFuncWrapper[string, int, int, float32]{}
+)
+
+// ProcessCloser processes a keyed value using the state store of its partition.
+// Process is called concurrently for keys in different partitions, while values of the same key are processed in order.
+// Ownership of values follows processor.ProcessCloser, and processor.ErrDrop drops the value.
+type ProcessCloser[K comparable, S, T, Res any] interface {
+	io.Closer
+	Process(ctx context.Context, key K, store *Store[K, S], val T) (Res, error)
+}
+
+type processFuncWrapper[K comparable, S, T, Res any] struct {
+	f func(context.Context, K, *Store[K, S], T) (Res, error)
+}
+
+func (s processFuncWrapper[K, S, T, Res]) Close() error { return nil }
+func (s processFuncWrapper[K, S, T, Res]) Process(ctx context.Context, key K, store *Store[K, S], val T) (Res, error) {
+	return s.f(ctx, key, store, val)
+}
+
+func Func[K comparable, S, T, Res any](f func(ctx context.Context, key K, store *Store[K, S], val T) (Res, error)) processFuncWrapper[K, S, T, Res] {
+	return processFuncWrapper[K, S, T, Res]{
+		f: f,
+	}
+}
+
+// Node is a stateful processor that partitions its input by key and processes every partition on its own worker.
+// Results of different keys are emitted in completion order, results of the same key in input order.
+type Node[K comparable, S, Tin, To any] struct {
+	graco.NodeBase
+	name    string
+	key     func(Tin) K
+	ttl     time.Duration
+	input   graco.SourceEdge[Tin]
+	output  graco.SourceEdge[To]
+	process ProcessCloser[K, S, Tin, To]
+	stores  []*Store[K, S]
+	expired atomic.Uint64
+}
+
+// New creates a keyed processor node.
+// partitions: Number of partitions, each with its own worker and state store.
+// ttl: Time to live of per-key state since it was last put. Zero keeps states until they are deleted.
+// key: Extracts the key of a value.
+func New[K comparable, S, Tin, To any](name string, partitions int, ttl time.Duration, key func(Tin) K, process ProcessCloser[K, S, Tin, To]) *Node[K, S, Tin, To] {
+	if partitions < 1 {
+		partitions = 1
+	}
+	res := &Node[K, S, Tin, To]{
+		name:    name,
+		key:     key,
+		ttl:     ttl,
+		process: process,
+		stores:  make([]*Store[K, S], partitions),
+	}
+	for i := range res.stores {
+		res.stores[i] = NewStore[K, S](ttl)
+	}
+	return res
+}
+
+// Close closes the processor and releases all states.
+func (n *Node[K, S, T, To]) Close() error {
+	return n.CloseOnce(func() error {
+		var err error
+		if n.process != nil {
+			err = n.process.Close()
+		}
+		for _, s := range n.stores {
+			err = errors.Join(err, s.Close())
+		}
+		return errors.Join(err, n.CloseOutputs(n.output))
+	})
+}
+func (n *Node[K, S, T, To]) Name() string { return n.name }
+
+// Expired returns the number of expired values discarded by the node.
+func (n *Node[K, S, T, To]) Expired() uint64 { return n.expired.Load() }
+
+func (n *Node[K, S, T, To]) Connect(in graco.SourceEdge[T]) (graco.SourceEdge[To], error) {
+	n.input = in
+	err := in.Connect(n)
+	if err != nil {
+		return nil, err
+	}
+	n.output, err = graco.NewSourceEdge[To]("o", n, 1, false)
+	return n.output, err
+}
+
+type keyedValue[K comparable, T any] struct {
+	key K
+	val T
+}
+
+func (n *Node[K, S, T, To]) Start(pctx context.Context) error {
+	defer n.CloseOutputs(n.output)
+	if err := graco.IsEdgeValid(n.input); err != nil {
+		return err
+	}
+	if err := graco.IsEdgeValid(n.output); err != nil {
+		return err
+	}
+
+	if n.process == nil || n.key == nil {
+		return errors.New("processor nil")
+	}
+
+	ctx, cancel := context.WithCancelCause(pctx)
+	defer cancel(nil)
+
+	var wg sync.WaitGroup
+	partitions := make([]chan keyedValue[K, T], len(n.stores))
+	for i := range partitions {
+		partitions[i] = make(chan keyedValue[K, T], 1)
+		wg.Add(1)
+		go func(in <-chan keyedValue[K, T], store *Store[K, S]) {
+			defer wg.Done()
+			if err := n.work(ctx, in, store); err != nil {
+				cancel(err)
+			}
+		}(partitions[i], n.stores[i])
+	}
+
+	err := n.dispatch(ctx, partitions)
+	for _, p := range partitions {
+		close(p)
+	}
+	wg.Wait()
+
+	// Release values left to failed workers.
+	for _, p := range partitions {
+		for kv := range p {
+			err = errors.Join(err, graco.Release(kv.val))
+		}
+	}
+
+	// A failed worker cancels the node with its error.
+	if pctx.Err() == nil && ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+	return err
+}
+
+// dispatch routes input values to the workers of their partitions.
+func (n *Node[K, S, T, To]) dispatch(ctx context.Context, partitions []chan keyedValue[K, T]) error {
+	for {
+		val, err := n.input.Recv(ctx)
+		if err != nil {
+			return err
+		}
+		if expired, err := graco.DropExpired(val); expired {
+			n.expired.Add(1)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := n.key(val)
+		select {
+		case <-ctx.Done():
+			return errors.Join(context.Cause(ctx), graco.Release(val))
+		case partitions[Partition(Hash(key), len(partitions))] <- keyedValue[K, T]{key: key, val: val}:
+		}
+	}
+}
+
+// work processes the values of a partition and expires its states.
+func (n *Node[K, S, T, To]) work(ctx context.Context, in <-chan keyedValue[K, T], store *Store[K, S]) error {
+	var tick <-chan time.Time
+	if n.ttl > 0 {
+		// States are expired twice per ttl, but not more often than every millisecond.
+		interval := n.ttl / 2
+		if interval < time.Millisecond {
+			interval = time.Millisecond
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		var (
+			kv keyedValue[K, T]
+			ok bool
+		)
+		select {
+		case now := <-tick:
+			if _, err := store.Expire(now); err != nil {
+				return err
+			}
+			continue
+		case kv, ok = <-in:
+			if !ok {
+				return nil
+			}
+		}
+
+		res, err := n.process.Process(ctx, kv.key, store, kv.val)
+		if errors.Is(err, processor.ErrDrop) {
+			if err := graco.Release(kv.val); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return errors.Join(err, graco.Release(kv.val))
+		}
+		graco.Untrack(kv.val)
+		graco.Track(res)
+
+		if err := n.output.Send(ctx, res); err != nil {
+			return errors.Join(err, graco.Release(res))
+		}
+	}
+}
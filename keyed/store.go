@@ -0,0 +1,95 @@
+package keyed
+
+import (
+	"errors"
+	"time"
+
+	"github.com/itohio/graco"
+)
+
+type entry[S any] struct {
+	state    S
+	deadline time.Time
+}
+
+// Store holds state per key. States that were not put for longer than the time to live expire.
+// States implementing io.Closer are closed when they are deleted or expire.
+// A Store is not safe for concurrent use; Node gives every partition its own Store.
+type Store[K comparable, S any] struct {
+	ttl     time.Duration
+	entries map[K]*entry[S]
+}
+
+// NewStore creates a store. Zero ttl keeps states until they are deleted.
+func NewStore[K comparable, S any](ttl time.Duration) *Store[K, S] {
+	return &Store[K, S]{
+		ttl:     ttl,
+		entries: make(map[K]*entry[S]),
+	}
+}
+
+// Get returns the state of a key.
+func (s *Store[K, S]) Get(key K) (S, bool) {
+	e, ok := s.entries[key]
+	if !ok {
+		var zero S
+		return zero, false
+	}
+	return e.state, true
+}
+
+// Put sets the state of a key and renews its time to live. A replaced state is not closed.
+func (s *Store[K, S]) Put(key K, state S) {
+	e, ok := s.entries[key]
+	if !ok {
+		e = &entry[S]{}
+		s.entries[key] = e
+	}
+	e.state = state
+	if s.ttl > 0 {
+		e.deadline = time.Now().Add(s.ttl)
+	}
+}
+
+// Delete removes and releases the state of a key.
+func (s *Store[K, S]) Delete(key K) error {
+	e, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	delete(s.entries, key)
+	return graco.Release(e.state)
+}
+
+// Expire removes and releases states whose time to live elapsed before now. Returns the number of expired states.
+func (s *Store[K, S]) Expire(now time.Time) (int, error) {
+	if s.ttl <= 0 {
+		return 0, nil
+	}
+	var (
+		n   int
+		err error
+	)
+	for key, e := range s.entries {
+		if now.Before(e.deadline) {
+			continue
+		}
+		delete(s.entries, key)
+		err = errors.Join(err, graco.Release(e.state))
+		n++
+	}
+	return n, err
+}
+
+// Len returns the number of keys with state.
+func (s *Store[K, S]) Len() int { return len(s.entries) }
+
+// Close releases all states.
+func (s *Store[K, S]) Close() error {
+	var err error
+	for key, e := range s.entries {
+		delete(s.entries, key)
+		err = errors.Join(err, graco.Release(e.state))
+	}
+	return err
+}
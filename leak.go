@@ -0,0 +1,82 @@
+package graco
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var leakDetector atomic.Pointer[LeakDetector]
+
+// LeakDetector tracks values that were allocated but never released. It is meant to be used in tests.
+type LeakDetector struct {
+	mu   sync.Mutex
+	live map[any]string
+}
+
+// EnableLeakDetection installs a new global leak detector and returns it.
+func EnableLeakDetection() *LeakDetector {
+	d := &LeakDetector{
+		live: make(map[any]string),
+	}
+	leakDetector.Store(d)
+	return d
+}
+
+// DisableLeakDetection removes the global leak detector.
+func DisableLeakDetection() {
+	leakDetector.Store(nil)
+}
+
+// Outstanding returns the number of values that were not released.
+func (d *LeakDetector) Outstanding() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.live)
+}
+
+// Check returns an error listing allocation stacks of values that were not released.
+func (d *LeakDetector) Check() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.live) == 0 {
+		return nil
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d values leaked", len(d.live))
+	for val, stack := range d.live {
+		fmt.Fprintf(&sb, "\n%T allocated at:\n%s", val, stack)
+	}
+	return fmt.Errorf("%s", sb.String())
+}
+
+func (d *LeakDetector) track(val any) {
+	stack := callers(4)
+	d.mu.Lock()
+	d.live[val] = stack
+	d.mu.Unlock()
+}
+
+func (d *LeakDetector) untrack(val any) {
+	d.mu.Lock()
+	delete(d.live, val)
+	d.mu.Unlock()
+}
+
+// callers formats the stack of the caller skipping skip frames.
+func callers(skip int) string {
+	var pcs [16]uintptr
+	n := runtime.Callers(skip, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	var sb strings.Builder
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&sb, "\t%s\n\t\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,74 @@
+package graco
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	_ SourceEdge[int]   = (*MultiSourceEdge[int])(nil)
+	_ MultiProducerEdge = (*MultiSourceEdge[int])(nil)
+)
+
+// MultiSourceEdge is a ChannelSourceEdge that can be written by several source nodes.
+// The underlying channel is closed only when the last attached producer closes the edge.
+type MultiSourceEdge[T any] struct {
+	*ChannelSourceEdge[T]
+	mu        sync.Mutex
+	producers []Node
+	refs      int
+	closed    bool
+}
+
+// NewMultiSourceEdge creates a MultiSourceEdge[T] instance without producers.
+// Producers are added using Attach.
+// cap: Capacity of the underlying channel (buffer size)
+func NewMultiSourceEdge[T any](name string, cap int) (*MultiSourceEdge[T], error) {
+	e, err := NewSourceEdge[T](name, nil, cap, false)
+	if err != nil {
+		return nil, err
+	}
+	res := &MultiSourceEdge[T]{
+		ChannelSourceEdge: e,
+	}
+	return res, nil
+}
+
+func (e *MultiSourceEdge[T]) Attach(src Node) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return errors.New("edge closed")
+	}
+	if e.src == nil {
+		e.src = src
+	}
+	e.producers = append(e.producers, src)
+	e.refs++
+	return nil
+}
+
+func (e *MultiSourceEdge[T]) Producers() []Node {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	res := make([]Node, len(e.producers))
+	copy(res, e.producers)
+	return res
+}
+
+// Close releases one producer reference. The channel is closed when no producers remain.
+func (e *MultiSourceEdge[T]) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil
+	}
+	if e.refs > 0 {
+		e.refs--
+	}
+	if e.refs > 0 {
+		return nil
+	}
+	e.closed = true
+	return e.ChannelSourceEdge.Close()
+}
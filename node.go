@@ -2,9 +2,14 @@ package graco
 
 import (
 	"context"
+	"errors"
 	"io"
+	"sync"
 )
 
+// ErrStop is returned by nodes, or by functions they call, to finish a node early without failing the graph.
+var ErrStop = errors.New("stop")
+
 type Node interface {
 	io.Closer
 	Name() string
@@ -12,5 +17,34 @@ type Node interface {
 	// Connect(A TypedEdge[TA], B TypedEdge[TB]) (TypedEdge[ResA], TypedEdge[ResB], error)
 }
 
+// NodeBase implements the ownership model shared by nodes: a node owns the edges it produces and closes them
+// exactly once, either when Start returns so that consumers observe io.EOF, or when the node is closed.
+// Close of a node is idempotent.
 type NodeBase struct {
+	closeOnce  sync.Once
+	closeErr   error
+	outputOnce sync.Once
+	outputErr  error
+}
+
+// CloseOnce calls f on the first call only. Subsequent calls return the error of the first call.
+func (b *NodeBase) CloseOnce(f func() error) error {
+	b.closeOnce.Do(func() {
+		b.closeErr = f()
+	})
+	return b.closeErr
+}
+
+// CloseOutputs closes output edges on the first call only. Nil edges are skipped.
+func (b *NodeBase) CloseOutputs(edges ...Edge) error {
+	b.outputOnce.Do(func() {
+		es := make([]error, len(edges))
+		for i, e := range edges {
+			if e != nil {
+				es[i] = e.Close()
+			}
+		}
+		b.outputErr = errors.Join(es...)
+	})
+	return b.outputErr
 }
@@ -0,0 +1,173 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/itohio/graco"
+)
+
+var (
+	_ AggregateCloser[int, float32] = aggregateFuncWrapper[int, float32]{}
+)
+
+// AggregateCloser accumulates values of type T and emits values of type Res on its own schedule.
+// Add takes ownership of the value only when it succeeds. If it returns ErrDrop or an error, the node releases the value.
+// Returning ErrStop from Add flushes the state and finishes the node after Add took ownership of the value.
+// Close must release any accumulated state that was not flushed.
+type AggregateCloser[T, Res any] interface {
+	io.Closer
+	// Add accumulates a value. It may emit results, e.g. when a group is complete.
+	Add(ctx context.Context, val T, emit Emit[Res]) error
+	// Flush emits results from the accumulated state. It is called periodically and when the input is exhausted.
+	Flush(ctx context.Context, emit Emit[Res]) error
+}
+
+type aggregateFuncWrapper[T, Res any] struct {
+	add   func(context.Context, T, Emit[Res]) error
+	flush func(context.Context, Emit[Res]) error
+}
+
+func (s aggregateFuncWrapper[T, Res]) Close() error { return nil }
+func (s aggregateFuncWrapper[T, Res]) Add(ctx context.Context, val T, emit Emit[Res]) error {
+	return s.add(ctx, val, emit)
+}
+func (s aggregateFuncWrapper[T, Res]) Flush(ctx context.Context, emit Emit[Res]) error {
+	if s.flush == nil {
+		return nil
+	}
+	return s.flush(ctx, emit)
+}
+
+// AggregateFunc wraps functions that accumulate and flush state, which is typically captured by both closures.
+// flush may be nil.
+func AggregateFunc[T, Res any](add func(context.Context, T, Emit[Res]) error, flush func(context.Context, Emit[Res]) error) aggregateFuncWrapper[T, Res] {
+	return aggregateFuncWrapper[T, Res]{
+		add:   add,
+		flush: flush,
+	}
+}
+
+// AggregateNode is a stateful processor that accumulates input values and emits results on its own schedule.
+type AggregateNode[Tin, To any] struct {
+	graco.NodeBase
+	name     string
+	interval time.Duration
+	input    graco.SourceEdge[Tin]
+	output   graco.SourceEdge[To]
+	agg      AggregateCloser[Tin, To]
+	expired  atomic.Uint64
+}
+
+// NewAggregate creates an aggregating processor node.
+// interval: Period of flushes. Zero flushes only when the input is exhausted.
+func NewAggregate[Tin, To any](name string, interval time.Duration, agg AggregateCloser[Tin, To]) *AggregateNode[Tin, To] {
+	res := &AggregateNode[Tin, To]{
+		name:     name,
+		interval: interval,
+		agg:      agg,
+	}
+	return res
+}
+
+func (n *AggregateNode[T, To]) Close() error {
+	return n.CloseOnce(func() error {
+		var err error
+		if n.agg != nil {
+			err = n.agg.Close()
+		}
+		return errors.Join(err, n.CloseOutputs(n.output))
+	})
+}
+func (n *AggregateNode[T, To]) Name() string { return n.name }
+
+// Expired returns the number of expired values discarded by the node.
+func (n *AggregateNode[T, To]) Expired() uint64 { return n.expired.Load() }
+
+func (n *AggregateNode[T, To]) Connect(in graco.SourceEdge[T]) (graco.SourceEdge[To], error) {
+	n.input = in
+	err := in.Connect(n)
+	if err != nil {
+		return nil, err
+	}
+	n.output, err = graco.NewSourceEdge[To]("o", n, 1, false)
+	return n.output, err
+}
+
+// Start accumulates input values until the input is exhausted, after which the state is flushed.
+func (n *AggregateNode[T, To]) Start(ctx context.Context) error {
+	defer n.CloseOutputs(n.output)
+	if err := graco.IsEdgeValid(n.input); err != nil {
+		return err
+	}
+	if err := graco.IsEdgeValid(n.output); err != nil {
+		return err
+	}
+
+	if n.agg == nil {
+		return errors.New("aggregator nil")
+	}
+
+	emit := emitter(ctx, n.output)
+	rctx, cancel := n.deadline(ctx)
+	defer func() { cancel() }()
+	for {
+		val, err := n.input.Recv(rctx)
+		if errors.Is(err, io.EOF) {
+			if err := n.agg.Flush(ctx, emit); err != nil {
+				return err
+			}
+			return err
+		}
+		if err != nil && ctx.Err() == nil && rctx.Err() != nil {
+			// The flush interval elapsed.
+			cancel()
+			if err := n.agg.Flush(ctx, emit); err != nil {
+				return err
+			}
+			rctx, cancel = n.deadline(ctx)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if expired, err := graco.DropExpired(val); expired {
+			n.expired.Add(1)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = n.agg.Add(ctx, val, emit)
+		if errors.Is(err, ErrStop) {
+			graco.Untrack(val)
+			if err := n.agg.Flush(ctx, emit); err != nil {
+				return err
+			}
+			return err
+		}
+		if errors.Is(err, ErrDrop) {
+			if err := graco.Release(val); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return errors.Join(err, graco.Release(val))
+		}
+		graco.Untrack(val)
+	}
+}
+
+// deadline returns a context for receiving values that expires at the next flush.
+func (n *AggregateNode[T, To]) deadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if n.interval <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, n.interval)
+}
@@ -0,0 +1,259 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/itohio/graco"
+)
+
+// PoolNode processes values on a pool of workers whose size follows the load. Results are emitted in completion order.
+//
+// Every interval the pool estimates the number of workers needed to keep up with the load from the time spent
+// processing and the number of values queued in the input edge, if it implements graco.Queue.
+// The pool grows immediately and shrinks by one idle worker per interval.
+// The processor must be safe for concurrent use.
+type PoolNode[Tin, To any] struct {
+	graco.NodeBase
+	name     string
+	min, max int
+	interval time.Duration
+	input    graco.SourceEdge[Tin]
+	output   graco.SourceEdge[To]
+	process  ProcessCloser[Tin, To]
+	expired  atomic.Uint64
+
+	workers   atomic.Int32
+	active    atomic.Int32
+	processed atomic.Int64
+	busy      atomic.Int64 // processing time in nanoseconds
+}
+
+// NewPool creates an autoscaling unordered processor node.
+// min, max: Bounds of the number of workers. min is at least 1.
+// interval: Period of scaling decisions.
+func NewPool[Tin, To any](name string, min, max int, interval time.Duration, processor ProcessCloser[Tin, To]) *PoolNode[Tin, To] {
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+	if interval <= 0 {
+		interval = 100 * time.Millisecond
+	}
+	res := &PoolNode[Tin, To]{
+		name:     name,
+		min:      min,
+		max:      max,
+		interval: interval,
+		process:  processor,
+	}
+	return res
+}
+
+func (n *PoolNode[T, To]) Close() error {
+	return n.CloseOnce(func() error {
+		var err error
+		if n.process != nil {
+			err = n.process.Close()
+		}
+		return errors.Join(err, n.CloseOutputs(n.output))
+	})
+}
+func (n *PoolNode[T, To]) Name() string { return n.name }
+
+// Expired returns the number of expired values discarded by the node.
+func (n *PoolNode[T, To]) Expired() uint64 { return n.expired.Load() }
+
+// Workers returns the current number of workers.
+func (n *PoolNode[T, To]) Workers() int { return int(n.workers.Load()) }
+
+func (n *PoolNode[T, To]) Connect(in graco.SourceEdge[T]) (graco.SourceEdge[To], error) {
+	n.input = in
+	err := in.Connect(n)
+	if err != nil {
+		return nil, err
+	}
+	n.output, err = graco.NewSourceEdge[To]("o", n, 1, false)
+	return n.output, err
+}
+
+func (n *PoolNode[T, To]) Start(pctx context.Context) error {
+	defer n.CloseOutputs(n.output)
+	if err := graco.IsEdgeValid(n.input); err != nil {
+		return err
+	}
+	if err := graco.IsEdgeValid(n.output); err != nil {
+		return err
+	}
+
+	if n.process == nil {
+		return errors.New("processor nil")
+	}
+
+	ctx, cancel := context.WithCancelCause(pctx)
+	defer cancel(nil)
+
+	var (
+		jobs = make(chan T)
+		quit = make(chan struct{}, n.max)
+		wg   sync.WaitGroup
+	)
+	spawn := func() {
+		wg.Add(1)
+		n.workers.Add(1)
+		go func() {
+			defer wg.Done()
+			defer n.workers.Add(-1)
+			if err := n.work(ctx, jobs, quit); err != nil {
+				cancel(err)
+			}
+		}()
+	}
+	for i := 0; i < n.min; i++ {
+		spawn()
+	}
+
+	stop := make(chan struct{})
+	scaled := make(chan struct{})
+	go func() {
+		defer close(scaled)
+		n.scale(ctx, stop, quit, spawn)
+	}()
+
+	err := n.dispatch(ctx, jobs)
+	close(jobs)
+	close(stop)
+	<-scaled
+	wg.Wait()
+
+	// A failed worker cancels the node with its error.
+	if pctx.Err() == nil && ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+	return err
+}
+
+// dispatch hands input values to idle workers.
+func (n *PoolNode[T, To]) dispatch(ctx context.Context, jobs chan<- T) error {
+	for {
+		val, err := n.input.Recv(ctx)
+		if err != nil {
+			return err
+		}
+		if expired, err := graco.DropExpired(val); expired {
+			n.expired.Add(1)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(context.Cause(ctx), graco.Release(val))
+		case jobs <- val:
+		}
+	}
+}
+
+// work processes values until the jobs are exhausted or it is asked to quit.
+func (n *PoolNode[T, To]) work(ctx context.Context, jobs <-chan T, quit <-chan struct{}) error {
+	for {
+		var (
+			val T
+			ok  bool
+		)
+		select {
+		case <-quit:
+			return nil
+		case val, ok = <-jobs:
+			if !ok {
+				return nil
+			}
+		}
+
+		n.active.Add(1)
+		start := time.Now()
+		res, err := n.process.Process(ctx, val)
+		n.busy.Add(int64(time.Since(start)))
+		n.processed.Add(1)
+		n.active.Add(-1)
+
+		if errors.Is(err, ErrDrop) {
+			if err := graco.Release(val); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return errors.Join(err, graco.Release(val))
+		}
+		graco.Untrack(val)
+		graco.Track(res)
+
+		if err := n.output.Send(ctx, res); err != nil {
+			return errors.Join(err, graco.Release(res))
+		}
+	}
+}
+
+// scale adjusts the number of workers every interval until stopped.
+func (n *PoolNode[T, To]) scale(ctx context.Context, stop <-chan struct{}, quit chan<- struct{}, spawn func()) {
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+
+	queue, _ := n.input.(graco.Queue)
+	running := n.min
+	var latency time.Duration
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		busy := time.Duration(n.busy.Swap(0))
+		if processed := n.processed.Swap(0); processed > 0 {
+			latency = busy / time.Duration(processed)
+		}
+		depth := 0
+		if queue != nil {
+			depth = queue.Len()
+		}
+
+		// Workers kept busy during the last interval plus workers needed to drain the queue within the next one.
+		needed := (busy + time.Duration(depth)*latency + n.interval - 1) / n.interval
+		desired := int(needed)
+		active := int(n.active.Load())
+		if depth > 0 && active >= running && desired <= running {
+			// Values are waiting while every worker is busy, e.g. with calls longer than the interval.
+			desired = running + 1
+		}
+		if desired < n.min {
+			desired = n.min
+		}
+		if desired > n.max {
+			desired = n.max
+		}
+
+		switch {
+		case desired > running:
+			for ; running < desired; running++ {
+				spawn()
+			}
+		case desired < running && active < running:
+			select {
+			case quit <- struct{}{}:
+				running--
+			default:
+			}
+		}
+	}
+}
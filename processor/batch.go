@@ -0,0 +1,122 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/itohio/graco"
+)
+
+// BatchNode is a batch-aware variant of Node. It receives values in batches of up to max values,
+// processes them one by one and sends the results as a single batch.
+type BatchNode[Tin, To any] struct {
+	graco.NodeBase
+	name    string
+	input   graco.SourceEdge[Tin]
+	output  graco.SourceEdge[To]
+	process ProcessCloser[Tin, To]
+	max     int
+	linger  time.Duration
+}
+
+// NewBatch creates a BatchNode that collects up to max values or waits at most linger for a batch to fill.
+func NewBatch[Tin, To any](name string, max int, linger time.Duration, processor ProcessCloser[Tin, To]) *BatchNode[Tin, To] {
+	if max < 1 {
+		max = 1
+	}
+	res := &BatchNode[Tin, To]{
+		name:    name,
+		process: processor,
+		max:     max,
+		linger:  linger,
+	}
+	return res
+}
+
+func (n *BatchNode[T, To]) Close() error {
+	return n.CloseOnce(func() error {
+		var err error
+		if n.process != nil {
+			err = n.process.Close()
+		}
+		return errors.Join(err, n.CloseOutputs(n.output))
+	})
+}
+func (n *BatchNode[T, To]) Name() string { return n.name }
+
+func (n *BatchNode[T, To]) Connect(in graco.SourceEdge[T]) (graco.SourceEdge[To], error) {
+	n.input = in
+	err := in.Connect(n)
+	if err != nil {
+		return nil, err
+	}
+	n.output, err = graco.NewSourceEdge[To]("o", n, n.max, false)
+	return n.output, err
+}
+
+func (n *BatchNode[T, To]) Start(ctx context.Context) error {
+	defer n.CloseOutputs(n.output)
+	if err := graco.IsEdgeValid(n.input); err != nil {
+		return err
+	}
+	if err := graco.IsEdgeValid(n.output); err != nil {
+		return err
+	}
+
+	if n.process == nil {
+		return errors.New("processor nil")
+	}
+
+	res := make([]To, 0, n.max)
+	for {
+		vals, err := graco.RecvBatch(ctx, n.input, n.max, n.linger)
+		if err != nil {
+			return err
+		}
+
+		res = res[:0]
+		for i, val := range vals {
+			r, err := n.process.Process(ctx, val)
+			if errors.Is(err, ErrDrop) {
+				if err := graco.Release(val); err != nil {
+					return errors.Join(err, releaseAll(vals[i+1:]), releaseAll(res))
+				}
+				continue
+			}
+			if errors.Is(err, ErrStop) {
+				// Results processed so far are still sent.
+				err = errors.Join(err, graco.Release(val), releaseAll(vals[i+1:]))
+				return errors.Join(err, n.send(ctx, res))
+			}
+			if err != nil {
+				return errors.Join(err, graco.Release(val), releaseAll(vals[i+1:]), releaseAll(res))
+			}
+			graco.Untrack(val)
+			graco.Track(r)
+			res = append(res, r)
+		}
+
+		if err := n.send(ctx, res); err != nil {
+			return err
+		}
+	}
+}
+
+// send sends results as a batch, releasing results that were not sent.
+func (n *BatchNode[T, To]) send(ctx context.Context, res []To) error {
+	sent, err := graco.SendBatch(ctx, n.output, res)
+	if err != nil {
+		return errors.Join(err, releaseAll(res[sent:]))
+	}
+	return nil
+}
+
+// releaseAll releases values that will never reach a consumer.
+func releaseAll[T any](vals []T) error {
+	var err error
+	for _, val := range vals {
+		err = errors.Join(err, graco.Release(val))
+	}
+	return err
+}
@@ -0,0 +1,69 @@
+package processor_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/itohio/graco"
+	"github.com/itohio/graco/processor"
+	"github.com/itohio/graco/sink"
+	"github.com/itohio/graco/source"
+)
+
+func TestBatchNodeStop(t *testing.T) {
+	d := graco.EnableLeakDetection()
+	defer graco.DisableLeakDetection()
+
+	i := 0
+	src := source.New[*graco.Shared[int]]("src", source.Func[*graco.Shared[int]](func(ctx context.Context) (*graco.Shared[int], error) {
+		if i == 5 {
+			return nil, io.EOF
+		}
+		i++
+		return graco.NewShared(i-1, nil), nil
+	}))
+	batch := processor.NewBatch[*graco.Shared[int], *graco.Shared[int]]("batch", 8, 50*time.Millisecond, processor.Func[*graco.Shared[int], *graco.Shared[int]](
+		func(ctx context.Context, val *graco.Shared[int]) (*graco.Shared[int], error) {
+			if val.Val() == 3 {
+				return nil, processor.ErrStop
+			}
+			return val, nil
+		},
+	))
+	var got []int
+	snk := sink.NewFunc[*graco.Shared[int]]("sink", sink.Func[*graco.Shared[int]](func(ctx context.Context, val *graco.Shared[int]) error {
+		got = append(got, val.Val())
+		return val.Close()
+	}))
+
+	o1, err := src.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	o2, err := batch.Connect(o1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := snk.Connect(o2); err != nil {
+		t.Fatal(err)
+	}
+
+	g := graco.New()
+	g.AddNode(0, src, batch, snk)
+	g.AddEdge(0, o1, o2)
+	if err := g.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 3 || got[0] != 0 || got[1] != 1 || got[2] != 2 {
+		t.Errorf("got %v, want [0 1 2]", got)
+	}
+	if err := d.Check(); err != nil {
+		t.Error(err)
+	}
+}
@@ -1,15 +1,13 @@
 package processor
 
-import (
-	"io"
-	"sync"
-)
+import "io"
 
 // Blob interface represents a type that is an intermediary between actual type and
 // bytes array. For example it can be a result of a JSON or Protobuf marshaller.
 type Blob[T any] interface {
 	io.Closer
 	Val() T
+	// Data returns the encoded bytes. They must not be used after the blob is closed.
 	Data() []byte
 }
 
@@ -29,30 +27,39 @@ func NewBlob[T any](val T, bytes []byte) bytesBlob[T] {
 	}
 }
 
+// poolBlob is a blob whose buffer is returned to a BlobPool when it is closed.
 type poolBlob[T any] struct {
-	bytesBlob[T]
-	p *sync.Pool
+	buf *[]byte
+	val T
+	p   *BlobPool
 }
 
-func (b poolBlob[T]) Val() T       { return b.val }
-func (b poolBlob[T]) Data() []byte { return b.bytes }
+func (b *poolBlob[T]) Val() T { return b.val }
+func (b *poolBlob[T]) Data() []byte {
+	if b.buf == nil {
+		return nil
+	}
+	return *b.buf
+}
+
+// Close returns the buffer to the pool. Data must not be used afterwards. Close is idempotent.
 func (b *poolBlob[T]) Close() error {
-	bytes := b.bytes
-	pool := b.p
-	b.bytes = nil
+	if b.p == nil {
+		return nil
+	}
+	b.p.Put(b.buf)
+	b.buf = nil
 	b.p = nil
 	var zero T
 	b.val = zero
-	pool.Put(bytes)
 	return nil
 }
 
-func NewBlobWithPool[T any](p *sync.Pool, val T, bytes []byte) poolBlob[T] {
-	return poolBlob[T]{
-		bytesBlob: bytesBlob[T]{
-			bytes: bytes,
-			val:   val,
-		},
-		p: p,
+// NewBlobWithPool creates a blob that owns buf, which is returned to p when the blob is closed.
+func NewBlobWithPool[T any](p *BlobPool, val T, buf *[]byte) *poolBlob[T] {
+	return &poolBlob[T]{
+		buf: buf,
+		val: val,
+		p:   p,
 	}
 }
@@ -0,0 +1,210 @@
+package processor
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/itohio/graco"
+)
+
+var (
+	_ ProcessCloser[int, float32] = (*Cache[int, float32, int])(nil)
+)
+
+type cacheEntry[K comparable, Res any] struct {
+	key      K
+	res      Res
+	deadline time.Time
+}
+
+// cacheCall is a call to the processor that concurrent identical requests wait for.
+type cacheCall struct {
+	done chan struct{}
+	err  error
+}
+
+// Cache memoizes the results of a processor by a key derived from the value.
+// Concurrent requests with the same key are coalesced into a single call to the processor:
+// they share its result, or its error if it fails. Requests whose context is still live do not share
+// a cancellation or a timeout of the context of the call, but call the processor again instead.
+//
+// A value served from the cache is consumed by the cache, i.e. it is released.
+// Results are shared by every request with the same key, therefore results implementing io.Closer are cached only
+// if they implement graco.Retainer, e.g. graco.Shared, which is retained for every request and for the cache itself.
+// Other closers are never cached. The cache is safe for concurrent use.
+type Cache[T, Res any, K comparable] struct {
+	p    ProcessCloser[T, Res]
+	key  func(T) K
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[K]*list.Element
+	lru     *list.List
+	calls   map[K]*cacheCall
+
+	err error // errors releasing evicted results, reported by Close
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// Cached decorates the processor with an LRU cache.
+// size: Maximum number of cached results. Non-positive size is unbounded.
+// ttl: Time to live of a cached result. Zero ttl keeps results until they are evicted.
+// key: Derives the cache key from a value.
+func Cached[T, Res any, K comparable](p ProcessCloser[T, Res], size int, ttl time.Duration, key func(T) K) *Cache[T, Res, K] {
+	return &Cache[T, Res, K]{
+		p:       p,
+		key:     key,
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[K]*list.Element),
+		lru:     list.New(),
+		calls:   make(map[K]*cacheCall),
+	}
+}
+
+// Close releases the cached results and closes the processor.
+// It also reports errors that occurred releasing evicted results.
+func (c *Cache[T, Res, K]) Close() error {
+	c.mu.Lock()
+	err := c.err
+	c.err = nil
+	for e := c.lru.Front(); e != nil; e = e.Next() {
+		err = errors.Join(err, graco.Release(e.Value.(*cacheEntry[K, Res]).res))
+	}
+	c.entries = make(map[K]*list.Element)
+	c.lru.Init()
+	c.mu.Unlock()
+	return errors.Join(err, c.p.Close())
+}
+
+// Hits returns the number of requests served from the cache, including requests coalesced with a pending call.
+func (c *Cache[T, Res, K]) Hits() uint64 { return c.hits.Load() }
+
+// Misses returns the number of requests that called the processor.
+func (c *Cache[T, Res, K]) Misses() uint64 { return c.misses.Load() }
+
+// Len returns the number of cached results.
+func (c *Cache[T, Res, K]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *Cache[T, Res, K]) Process(ctx context.Context, val T) (Res, error) {
+	k := c.key(val)
+	for {
+		c.mu.Lock()
+		if res, ok := c.get(k); ok {
+			c.mu.Unlock()
+			c.hits.Add(1)
+			if err := graco.Release(val); err != nil {
+				var zero Res
+				return zero, errors.Join(err, graco.Release(res))
+			}
+			return res, nil
+		}
+		call, pending := c.calls[k]
+		if !pending {
+			call = &cacheCall{done: make(chan struct{})}
+			c.calls[k] = call
+		}
+		c.mu.Unlock()
+
+		if !pending {
+			return c.call(ctx, k, call, val)
+		}
+
+		select {
+		case <-ctx.Done():
+			var zero Res
+			return zero, context.Cause(ctx)
+		case <-call.done:
+		}
+		if call.err != nil && !(isContextErr(call.err) && ctx.Err() == nil) {
+			var zero Res
+			return zero, call.err
+		}
+		// The result is looked up again. If it was not cached, e.g. because it is not shareable
+		// or the call was canceled by the context of its caller, the request calls the processor itself.
+	}
+}
+
+// isContextErr reports whether an error was caused by a canceled context or an elapsed deadline.
+func isContextErr(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// call calls the processor and caches its result.
+func (c *Cache[T, Res, K]) call(ctx context.Context, k K, call *cacheCall, val T) (Res, error) {
+	c.misses.Add(1)
+	res, err := c.p.Process(ctx, val)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer close(call.done)
+	delete(c.calls, k)
+	call.err = err
+	if err == nil {
+		c.put(k, res)
+	}
+	return res, err
+}
+
+// get returns a cached result retained for the caller. Expired results are removed.
+func (c *Cache[T, Res, K]) get(k K) (Res, bool) {
+	var zero Res
+	e, ok := c.entries[k]
+	if !ok {
+		return zero, false
+	}
+	entry := e.Value.(*cacheEntry[K, Res])
+	if c.ttl > 0 && !time.Now().Before(entry.deadline) {
+		c.remove(e)
+		return zero, false
+	}
+	c.lru.MoveToFront(e)
+	if r, ok := any(entry.res).(graco.Retainer); ok {
+		r.Retain()
+	}
+	return entry.res, true
+}
+
+// put caches a shareable result, retaining it for the cache, and evicts the least recently used results.
+func (c *Cache[T, Res, K]) put(k K, res Res) {
+	if _, ok := any(res).(io.Closer); ok {
+		r, ok := any(res).(graco.Retainer)
+		if !ok {
+			return
+		}
+		r.Retain()
+	}
+
+	if e, ok := c.entries[k]; ok {
+		c.remove(e)
+	}
+	entry := &cacheEntry[K, Res]{key: k, res: res}
+	if c.ttl > 0 {
+		entry.deadline = time.Now().Add(c.ttl)
+	}
+	c.entries[k] = c.lru.PushFront(entry)
+	for c.size > 0 && c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+// remove removes a cached result and releases the reference held by the cache.
+func (c *Cache[T, Res, K]) remove(e *list.Element) {
+	entry := c.lru.Remove(e).(*cacheEntry[K, Res])
+	delete(c.entries, entry.key)
+	if err := graco.Release(entry.res); err != nil {
+		c.err = errors.Join(c.err, err)
+	}
+}
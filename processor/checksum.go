@@ -0,0 +1,118 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+var ErrChecksum = errors.New("checksum mismatch")
+
+// Checksum is a checksum algorithm used by Stamp and Verify. The checksum is stored as a trailer of the payload.
+type Checksum int
+
+const (
+	// CRC32 is a 4 byte big endian IEEE CRC-32 trailer.
+	CRC32 Checksum = iota
+	// SHA256 is a 32 byte SHA-256 digest trailer.
+	SHA256
+)
+
+func (c Checksum) size() int {
+	switch c {
+	case CRC32:
+		return crc32.Size
+	case SHA256:
+		return sha256.Size
+	}
+	return -1
+}
+
+func (c Checksum) append(dst, data []byte) []byte {
+	switch c {
+	case CRC32:
+		return binary.BigEndian.AppendUint32(dst, crc32.ChecksumIEEE(data))
+	case SHA256:
+		sum := sha256.Sum256(data)
+		return append(dst, sum[:]...)
+	}
+	return dst
+}
+
+// Stamp appends a checksum of the payload to blobs, copying them into buffers taken from p, if it is not nil.
+// The input blob is closed once stamped.
+func Stamp[T any](alg Checksum, p *BlobPool) (processFuncWrapper[Blob[T], Blob[T]], error) {
+	size := alg.size()
+	if size < 0 {
+		return processFuncWrapper[Blob[T], Blob[T]]{}, fmt.Errorf("unknown checksum %d", alg)
+	}
+	return Func[Blob[T], Blob[T]](
+		func(ctx context.Context, blob Blob[T]) (Blob[T], error) {
+			data := blob.Data()
+			var buf *[]byte
+			if p != nil {
+				buf = p.Get(len(data) + size)
+			} else {
+				b := make([]byte, 0, len(data)+size)
+				buf = &b
+			}
+			*buf = alg.append(append(*buf, data...), data)
+
+			val := blob.Val()
+			if err := blob.Close(); err != nil {
+				if p != nil {
+					p.Put(buf)
+				}
+				return nil, err
+			}
+			if p == nil {
+				return NewBlob[T](val, *buf), nil
+			}
+			return NewBlobWithPool[T](p, val, buf), nil
+		},
+	), nil
+}
+
+// Verify checks and strips the checksum trailer of blobs without copying the payload.
+// Blobs that fail verification are dropped if drop is set, otherwise ErrChecksum stops the node.
+func Verify[T any](alg Checksum, drop bool) (processFuncWrapper[Blob[T], Blob[T]], error) {
+	size := alg.size()
+	if size < 0 {
+		return processFuncWrapper[Blob[T], Blob[T]]{}, fmt.Errorf("unknown checksum %d", alg)
+	}
+	fail := ErrChecksum
+	if drop {
+		fail = ErrDrop
+	}
+	return Func[Blob[T], Blob[T]](
+		func(ctx context.Context, blob Blob[T]) (Blob[T], error) {
+			data := blob.Data()
+			if len(data) < size {
+				return nil, fail
+			}
+			payload := data[:len(data)-size]
+			var sum [sha256.Size]byte
+			if !bytes.Equal(alg.append(sum[:0], payload), data[len(payload):]) {
+				return nil, fail
+			}
+			return &sliceBlob[T]{Blob: blob, data: payload}, nil
+		},
+	), nil
+}
+
+// sliceBlob is a view of a part of the payload of another blob.
+type sliceBlob[T any] struct {
+	Blob[T]
+	data []byte
+}
+
+func (b *sliceBlob[T]) Data() []byte { return b.data }
+
+func (b *sliceBlob[T]) Close() error {
+	b.data = nil
+	return b.Blob.Close()
+}
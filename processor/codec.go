@@ -0,0 +1,46 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/itohio/graco/codec"
+)
+
+// Encode encodes values into blobs using c.
+func Encode[T any](c codec.Codec[T]) processFuncWrapper[T, Blob[T]] {
+	return Func[T, Blob[T]](
+		func(ctx context.Context, val T) (Blob[T], error) {
+			data, err := c.Marshal(val)
+			if err != nil {
+				return nil, err
+			}
+			return NewBlob[T](val, data), nil
+		},
+	)
+}
+
+// EncodePooled encodes values into buffers taken from p using c.
+// Codecs implementing codec.Appender encode directly into the pooled buffer.
+func EncodePooled[T any](c codec.Codec[T], p *BlobPool) processFuncWrapper[T, Blob[T]] {
+	if a, ok := c.(codec.Appender[T]); ok {
+		return MarshalPooled[T](p, a.AppendMarshal)
+	}
+	return MarshalPooled[T](p, func(dst []byte, val T) ([]byte, error) {
+		data, err := c.Marshal(val)
+		return append(dst, data...), err
+	})
+}
+
+// Decode decodes blobs using c. The blob is closed once it is decoded.
+func Decode[T any](c codec.Codec[T]) processFuncWrapper[Blob[T], T] {
+	return Func[Blob[T], T](
+		func(ctx context.Context, blob Blob[T]) (T, error) {
+			val, err := c.Unmarshal(blob.Data())
+			if err != nil {
+				// The node releases the blob on error.
+				return val, err
+			}
+			return val, blob.Close()
+		},
+	)
+}
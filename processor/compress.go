@@ -0,0 +1,152 @@
+package processor
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Compression is a compression algorithm used by Compress and Decompress.
+type Compression int
+
+const (
+	Gzip Compression = iota
+	Flate
+	Zlib
+)
+
+type compressWriter interface {
+	io.WriteCloser
+	Reset(io.Writer)
+}
+
+// Compress compresses blob payloads into buffers taken from p, if it is not nil.
+// Compressors are reused between values. The input blob is closed once compressed.
+// level: Compression level, e.g. flate.DefaultCompression.
+func Compress[T any](alg Compression, level int, p *BlobPool) (processFuncWrapper[Blob[T], Blob[T]], error) {
+	var newWriter func() (compressWriter, error)
+	switch alg {
+	case Gzip:
+		newWriter = func() (compressWriter, error) { return gzip.NewWriterLevel(nil, level) }
+	case Flate:
+		newWriter = func() (compressWriter, error) { return flate.NewWriter(nil, level) }
+	case Zlib:
+		newWriter = func() (compressWriter, error) { return zlib.NewWriterLevel(nil, level) }
+	default:
+		return processFuncWrapper[Blob[T], Blob[T]]{}, fmt.Errorf("unknown compression %d", alg)
+	}
+	// Validate the level early.
+	if _, err := newWriter(); err != nil {
+		return processFuncWrapper[Blob[T], Blob[T]]{}, err
+	}
+
+	var writers sync.Pool
+	return Func[Blob[T], Blob[T]](
+		func(ctx context.Context, blob Blob[T]) (Blob[T], error) {
+			zw, ok := writers.Get().(compressWriter)
+			if !ok {
+				var err error
+				if zw, err = newWriter(); err != nil {
+					return nil, err
+				}
+			}
+			defer writers.Put(zw)
+
+			data := blob.Data()
+			return transform(p, blob, len(data)/2, func(w io.Writer) error {
+				zw.Reset(w)
+				if _, err := zw.Write(data); err != nil {
+					return err
+				}
+				return zw.Close()
+			})
+		},
+	), nil
+}
+
+// Decompress decompresses blob payloads into buffers taken from p, if it is not nil.
+// Decompressors are reused between values. The input blob is closed once decompressed.
+func Decompress[T any](alg Compression, p *BlobPool) (processFuncWrapper[Blob[T], Blob[T]], error) {
+	var reset func(io.ReadCloser, io.Reader) (io.ReadCloser, error)
+	switch alg {
+	case Gzip:
+		reset = func(zr io.ReadCloser, r io.Reader) (io.ReadCloser, error) {
+			if zr == nil {
+				return gzip.NewReader(r)
+			}
+			return zr, zr.(*gzip.Reader).Reset(r)
+		}
+	case Flate:
+		reset = func(zr io.ReadCloser, r io.Reader) (io.ReadCloser, error) {
+			if zr == nil {
+				return flate.NewReader(r), nil
+			}
+			return zr, zr.(flate.Resetter).Reset(r, nil)
+		}
+	case Zlib:
+		reset = func(zr io.ReadCloser, r io.Reader) (io.ReadCloser, error) {
+			if zr == nil {
+				return zlib.NewReader(r)
+			}
+			return zr, zr.(zlib.Resetter).Reset(r, nil)
+		}
+	default:
+		return processFuncWrapper[Blob[T], Blob[T]]{}, fmt.Errorf("unknown compression %d", alg)
+	}
+
+	var readers sync.Pool
+	return Func[Blob[T], Blob[T]](
+		func(ctx context.Context, blob Blob[T]) (Blob[T], error) {
+			data := blob.Data()
+			zr, _ := readers.Get().(io.ReadCloser)
+			zr, err := reset(zr, bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			defer readers.Put(zr)
+
+			return transform(p, blob, len(data)*2, func(w io.Writer) error {
+				if _, err := io.Copy(w, zr); err != nil {
+					return err
+				}
+				return zr.Close()
+			})
+		},
+	), nil
+}
+
+// transform writes a new payload for blob into a buffer of at least size bytes and closes blob on success.
+func transform[T any](p *BlobPool, blob Blob[T], size int, write func(io.Writer) error) (Blob[T], error) {
+	var buf *[]byte
+	if p != nil {
+		buf = p.Get(size)
+	} else {
+		b := make([]byte, 0, size)
+		buf = &b
+	}
+	w := bytes.NewBuffer(*buf)
+	if err := write(w); err != nil {
+		if p != nil {
+			p.Put(buf)
+		}
+		return nil, err
+	}
+	*buf = w.Bytes()
+
+	val := blob.Val()
+	if err := blob.Close(); err != nil {
+		if p != nil {
+			p.Put(buf)
+		}
+		return nil, err
+	}
+	if p == nil {
+		return NewBlob[T](val, *buf), nil
+	}
+	return NewBlobWithPool[T](p, val, buf), nil
+}
@@ -0,0 +1,34 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/itohio/graco"
+)
+
+var (
+	_ ProcessCloser[graco.Envelope[int], graco.Envelope[float32]] = processEnvelopeWrapper[int, float32]{}
+)
+
+type processEnvelopeWrapper[T, Res any] struct {
+	p ProcessCloser[T, Res]
+}
+
+func (s processEnvelopeWrapper[T, Res]) Close() error { return s.p.Close() }
+func (s processEnvelopeWrapper[T, Res]) Process(ctx context.Context, val graco.Envelope[T]) (graco.Envelope[Res], error) {
+	if !val.Deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, val.Deadline)
+		defer cancel()
+	}
+	res, err := s.p.Process(ctx, val.Val)
+	return graco.Rewrap(val, res), err
+}
+
+// Enveloped adapts a processor of plain values to process envelopes. Envelope metadata is propagated to the result.
+// The envelope deadline, if any, is applied to the processing context.
+func Enveloped[T, Res any](p ProcessCloser[T, Res]) processEnvelopeWrapper[T, Res] {
+	return processEnvelopeWrapper[T, Res]{
+		p: p,
+	}
+}
@@ -0,0 +1,161 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+
+	"github.com/itohio/graco"
+)
+
+var (
+	_ FlatMapCloser[int, float32] = flatMapFuncWrapper[int, float32]{}
+)
+
+// Emit sends a result to the output of the node. It fails if the result cannot be delivered,
+// in which case the error must be returned by the caller.
+type Emit[T any] func(T) error
+
+// FlatMapCloser maps every value of type T to zero or more values of type Res.
+// FlatMap takes ownership of the value only when it succeeds. If it returns ErrDrop or an error,
+// the node releases the value, therefore FlatMap must not close it. Returning ErrStop finishes the node
+// after FlatMap took ownership of the value, e.g. after emitting it.
+type FlatMapCloser[T, Res any] interface {
+	io.Closer
+	FlatMap(ctx context.Context, val T, emit Emit[Res]) error
+}
+
+type flatMapFuncWrapper[T, Res any] struct {
+	f func(context.Context, T, Emit[Res]) error
+}
+
+func (s flatMapFuncWrapper[T, Res]) Close() error { return nil }
+func (s flatMapFuncWrapper[T, Res]) FlatMap(ctx context.Context, val T, emit Emit[Res]) error {
+	return s.f(ctx, val, emit)
+}
+
+// FlatMapFunc wraps a function that emits results using a callback.
+func FlatMapFunc[T, Res any](f func(context.Context, T, Emit[Res]) error) flatMapFuncWrapper[T, Res] {
+	return flatMapFuncWrapper[T, Res]{
+		f: f,
+	}
+}
+
+// SliceFunc wraps a function that returns a slice of results.
+func SliceFunc[T, Res any](f func(context.Context, T) ([]Res, error)) flatMapFuncWrapper[T, Res] {
+	return FlatMapFunc[T, Res](func(ctx context.Context, val T, emit Emit[Res]) error {
+		res, err := f(ctx, val)
+		if err != nil {
+			return err
+		}
+		for i, r := range res {
+			if err := emit(r); err != nil {
+				for _, r := range res[i+1:] {
+					err = errors.Join(err, graco.Release(r))
+				}
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FlatMapNode is a processor that emits zero or more results for every input value.
+type FlatMapNode[Tin, To any] struct {
+	graco.NodeBase
+	name    string
+	input   graco.SourceEdge[Tin]
+	output  graco.SourceEdge[To]
+	f       FlatMapCloser[Tin, To]
+	expired atomic.Uint64
+}
+
+func NewFlatMap[Tin, To any](name string, f FlatMapCloser[Tin, To]) *FlatMapNode[Tin, To] {
+	res := &FlatMapNode[Tin, To]{
+		name: name,
+		f:    f,
+	}
+	return res
+}
+
+func (n *FlatMapNode[T, To]) Close() error {
+	return n.CloseOnce(func() error {
+		var err error
+		if n.f != nil {
+			err = n.f.Close()
+		}
+		return errors.Join(err, n.CloseOutputs(n.output))
+	})
+}
+func (n *FlatMapNode[T, To]) Name() string { return n.name }
+
+// Expired returns the number of expired values discarded by the node.
+func (n *FlatMapNode[T, To]) Expired() uint64 { return n.expired.Load() }
+
+func (n *FlatMapNode[T, To]) Connect(in graco.SourceEdge[T]) (graco.SourceEdge[To], error) {
+	n.input = in
+	err := in.Connect(n)
+	if err != nil {
+		return nil, err
+	}
+	n.output, err = graco.NewSourceEdge[To]("o", n, 1, false)
+	return n.output, err
+}
+
+func (n *FlatMapNode[T, To]) Start(ctx context.Context) error {
+	defer n.CloseOutputs(n.output)
+	if err := graco.IsEdgeValid(n.input); err != nil {
+		return err
+	}
+	if err := graco.IsEdgeValid(n.output); err != nil {
+		return err
+	}
+
+	if n.f == nil {
+		return errors.New("flat mapper nil")
+	}
+
+	emit := emitter(ctx, n.output)
+	for {
+		val, err := n.input.Recv(ctx)
+		if err != nil {
+			return err
+		}
+
+		if expired, err := graco.DropExpired(val); expired {
+			n.expired.Add(1)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = n.f.FlatMap(ctx, val, emit)
+		if errors.Is(err, ErrStop) {
+			graco.Untrack(val)
+			return err
+		}
+		if errors.Is(err, ErrDrop) {
+			if err := graco.Release(val); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return errors.Join(err, graco.Release(val))
+		}
+		graco.Untrack(val)
+	}
+}
+
+// emitter creates an Emit that sends results to the output edge, releasing results that cannot be sent.
+func emitter[T any](ctx context.Context, output graco.SourceEdge[T]) Emit[T] {
+	return func(res T) error {
+		graco.Track(res)
+		if err := output.Send(ctx, res); err != nil {
+			return errors.Join(err, graco.Release(res))
+		}
+		return nil
+	}
+}
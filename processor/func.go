@@ -3,7 +3,9 @@ package processor
 import (
 	"context"
 	"encoding/json"
-	"errors"
+	"sync/atomic"
+
+	"github.com/itohio/graco/codec"
 )
 
 func MarshalJSON[T any]() processFuncWrapper[T, Blob[T]] {
@@ -15,12 +17,41 @@ func MarshalJSON[T any]() processFuncWrapper[T, Blob[T]] {
 	)
 }
 
+// MarshalJSONPooled encodes values into buffers taken from p. The buffers are returned to the pool
+// when the blobs are closed, e.g. by UnmarshalJSON or when they are dropped.
+func MarshalJSONPooled[T any](p *BlobPool) processFuncWrapper[T, Blob[T]] {
+	return EncodePooled[T](codec.JSON[T](), p)
+}
+
+// MarshalPooled encodes values into buffers taken from p using an append style marshal function.
+// The buffers are returned to the pool when the blobs are closed.
+// Buffers are sized after the last encoded value, so that values of similar size do not grow them.
+func MarshalPooled[T any](p *BlobPool, marshal func(dst []byte, val T) ([]byte, error)) processFuncWrapper[T, Blob[T]] {
+	var hint atomic.Int64
+	return Func[T, Blob[T]](
+		func(ctx context.Context, val T) (Blob[T], error) {
+			buf := p.Get(int(hint.Load()))
+			data, err := marshal(*buf, val)
+			*buf = data
+			if err != nil {
+				p.Put(buf)
+				return nil, err
+			}
+			hint.Store(int64(len(data)))
+			return NewBlobWithPool[T](p, val, buf), nil
+		},
+	)
+}
+
 func UnmarshalJSON[T any]() processFuncWrapper[Blob[T], T] {
 	return Func[Blob[T], T](
 		func(ctx context.Context, blob Blob[T]) (T, error) {
 			var val T
-			err := json.Unmarshal(blob.Data(), &val)
-			return val, errors.Join(err, blob.Close())
+			if err := json.Unmarshal(blob.Data(), &val); err != nil {
+				// The node releases the blob on error.
+				return val, err
+			}
+			return val, blob.Close()
 		},
 	)
 }
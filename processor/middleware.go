@@ -0,0 +1,195 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	_ ProcessCloser[int, float32] = middlewareWrapper[int, float32]{}
+)
+
+// ErrCircuitOpen is returned by CircuitBreaker while the circuit is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// Middleware decorates a call to a processor. It calls next zero or more times and returns the error of the call.
+// Middlewares are not typed, therefore the same middleware can be shared by processors of different types,
+// e.g. a circuit breaker or a bulkhead guarding a single service.
+type Middleware func(ctx context.Context, next func(context.Context) error) error
+
+type middlewareWrapper[T, Res any] struct {
+	p    ProcessCloser[T, Res]
+	call func(ctx context.Context, next func(context.Context) error) error
+}
+
+func (s middlewareWrapper[T, Res]) Close() error { return s.p.Close() }
+func (s middlewareWrapper[T, Res]) Process(ctx context.Context, val T) (Res, error) {
+	var res Res
+	err := s.call(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = s.p.Process(ctx, val)
+		return err
+	})
+	return res, err
+}
+
+// With decorates the processor with middlewares. The first middleware is the outermost one, e.g.
+// With(p, Retry(...), Timeout(...)) retries calls that time out individually.
+//
+// Processors do not take ownership of values when they fail, therefore a value can be processed again.
+func With[T, Res any](p ProcessCloser[T, Res], mw ...Middleware) middlewareWrapper[T, Res] {
+	call := func(ctx context.Context, next func(context.Context) error) error {
+		return next(ctx)
+	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		inner, m := call, mw[i]
+		call = func(ctx context.Context, next func(context.Context) error) error {
+			return m(ctx, func(ctx context.Context) error {
+				return inner(ctx, next)
+			})
+		}
+	}
+	return middlewareWrapper[T, Res]{
+		p:    p,
+		call: call,
+	}
+}
+
+// Retry retries failed calls with exponential backoff and jitter.
+// attempts: Maximum number of calls, including the first one.
+// base, max: The delay before the n-th retry is base*2^(n-1), capped at max, of which a random half is jittered.
+// retryable: Reports whether an error is worth retrying. If nil, every error is retried.
+// ErrDrop, ErrStop and errors occurring after the context is done are never retried.
+func Retry(attempts int, base, max time.Duration, retryable func(error) bool) Middleware {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if max < base {
+		max = base
+	}
+	return func(ctx context.Context, next func(context.Context) error) error {
+		delay := base
+		for i := 1; ; i++ {
+			err := next(ctx)
+			if err == nil || i >= attempts || errors.Is(err, ErrDrop) || errors.Is(err, ErrStop) || ctx.Err() != nil {
+				return err
+			}
+			if retryable != nil && !retryable(err) {
+				return err
+			}
+
+			if err := sleep(ctx, jitter(delay)); err != nil {
+				return err
+			}
+			if delay *= 2; delay > max || delay <= 0 {
+				delay = max
+			}
+		}
+	}
+}
+
+// jitter returns a random duration between d/2 and d.
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// sleep waits for d or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Timeout limits the duration of every call. The processor must observe its context.
+func Timeout(d time.Duration) Middleware {
+	return func(ctx context.Context, next func(context.Context) error) error {
+		if d <= 0 {
+			return next(ctx)
+		}
+		ctx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+		return next(ctx)
+	}
+}
+
+// CircuitBreaker stops calling the processor after failures consecutive failures and fails fast with ErrCircuitOpen.
+// After cooldown a single probing call is let through: if it succeeds the circuit closes, otherwise it opens again.
+// ErrDrop and ErrStop are not failures. Errors occurring after the context is done are ignored.
+// The breaker is safe for concurrent use.
+func CircuitBreaker(failures int, cooldown time.Duration) Middleware {
+	if failures < 1 {
+		failures = 1
+	}
+	var (
+		mu      sync.Mutex
+		failed  int
+		open    bool
+		probing bool
+		until   time.Time
+	)
+	return func(ctx context.Context, next func(context.Context) error) error {
+		mu.Lock()
+		probe := false
+		if open {
+			if probing || time.Now().Before(until) {
+				mu.Unlock()
+				return ErrCircuitOpen
+			}
+			probing, probe = true, true
+		}
+		mu.Unlock()
+
+		err := next(ctx)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if probe {
+			probing = false
+		}
+		switch {
+		case err == nil || errors.Is(err, ErrDrop) || errors.Is(err, ErrStop):
+			failed, open = 0, false
+		case ctx.Err() != nil:
+			// The call was canceled, which says nothing about the processor.
+		default:
+			failed++
+			if probe || failed >= failures {
+				open, until = true, time.Now().Add(cooldown)
+			}
+		}
+		return err
+	}
+}
+
+// Bulkhead limits the number of concurrent calls to n. Calls wait for a free slot until the context is done.
+// The bulkhead is safe for concurrent use and may be shared by several processors.
+func Bulkhead(n int) Middleware {
+	if n < 1 {
+		n = 1
+	}
+	sem := make(chan struct{}, n)
+	return func(ctx context.Context, next func(context.Context) error) error {
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case sem <- struct{}{}:
+		}
+		defer func() { <-sem }()
+		return next(ctx)
+	}
+}
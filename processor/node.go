@@ -4,25 +4,32 @@ import (
 	"context"
 	"errors"
 	"io"
+	"sync/atomic"
 
 	"github.com/itohio/graco"
 )
 
 var (
 	ErrDrop = errors.New("drop")
-	ErrStop = errors.New("stop")
+	// ErrStop finishes the node cleanly, see graco.ErrStop.
+	ErrStop = graco.ErrStop
 )
 
+// ProcessCloser processes values of type T into values of type Res.
+// Process takes ownership of the value only when it succeeds. If it returns ErrDrop, ErrStop or an error,
+// the node releases the value, therefore Process must not close it. ErrStop finishes the node without a result.
 type ProcessCloser[T, Res any] interface {
 	io.Closer
 	Process(context.Context, T) (Res, error)
 }
 
 type Node[Tin, To any] struct {
+	graco.NodeBase
 	name    string
 	input   graco.SourceEdge[Tin]
 	output  graco.SourceEdge[To]
 	process ProcessCloser[Tin, To]
+	expired atomic.Uint64
 }
 
 func New[Tin, To any](name string, processor ProcessCloser[Tin, To]) *Node[Tin, To] {
@@ -34,14 +41,19 @@ func New[Tin, To any](name string, processor ProcessCloser[Tin, To]) *Node[Tin,
 }
 
 func (n *Node[T, To]) Close() error {
-	err := n.process.Close()
-	if n.output == nil {
-		return err
-	}
-	return errors.Join(err, n.output.Close())
+	return n.CloseOnce(func() error {
+		var err error
+		if n.process != nil {
+			err = n.process.Close()
+		}
+		return errors.Join(err, n.CloseOutputs(n.output))
+	})
 }
 func (n *Node[T, To]) Name() string { return n.name }
 
+// Expired returns the number of expired values discarded by the node.
+func (n *Node[T, To]) Expired() uint64 { return n.expired.Load() }
+
 func (n *Node[T, To]) Connect(in graco.SourceEdge[T]) (graco.SourceEdge[To], error) {
 	n.input = in
 	err := in.Connect(n)
@@ -53,6 +65,7 @@ func (n *Node[T, To]) Connect(in graco.SourceEdge[T]) (graco.SourceEdge[To], err
 }
 
 func (n *Node[T, To]) Start(ctx context.Context) error {
+	defer n.CloseOutputs(n.output)
 	if err := graco.IsEdgeValid(n.input); err != nil {
 		return err
 	}
@@ -70,16 +83,29 @@ func (n *Node[T, To]) Start(ctx context.Context) error {
 			return err
 		}
 
+		if expired, err := graco.DropExpired(val); expired {
+			n.expired.Add(1)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
 		res, err := n.process.Process(ctx, val)
 		if errors.Is(err, ErrDrop) {
+			if err := graco.Release(val); err != nil {
+				return err
+			}
 			continue
 		}
 		if err != nil {
-			return err
+			return errors.Join(err, graco.Release(val))
 		}
+		graco.Untrack(val)
+		graco.Track(res)
 
 		if err := n.output.Send(ctx, res); err != nil {
-			return err
+			return errors.Join(err, graco.Release(res))
 		}
 	}
 }
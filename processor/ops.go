@@ -0,0 +1,117 @@
+package processor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/itohio/graco"
+)
+
+// NewMap creates a node that maps every value using f.
+// f takes ownership of the value only when it succeeds.
+func NewMap[A, B any](name string, f func(context.Context, A) (B, error)) *Node[A, B] {
+	return New[A, B](name, Func(f))
+}
+
+// NewFilter creates a node that emits only values for which pred returns true. Other values are released.
+func NewFilter[T any](name string, pred func(context.Context, T) (bool, error)) *Node[T, T] {
+	return New[T, T](name, Func(func(ctx context.Context, val T) (T, error) {
+		ok, err := pred(ctx, val)
+		if err == nil && !ok {
+			err = ErrDrop
+		}
+		return val, err
+	}))
+}
+
+// NewScan creates a node that folds every value into a running state using f and emits the state after every value.
+// f takes ownership of the value only when it succeeds.
+func NewScan[T, S any](name string, init S, f func(context.Context, S, T) (S, error)) *Node[T, S] {
+	state := init
+	return New[T, S](name, Func(func(ctx context.Context, val T) (S, error) {
+		s, err := f(ctx, state, val)
+		if err != nil {
+			return s, err
+		}
+		state = s
+		return s, nil
+	}))
+}
+
+// NewReduce creates a node that folds every value into a state using f and emits the final state
+// once the input is exhausted. If the input is empty, init is emitted.
+// f takes ownership of the value only when it succeeds.
+func NewReduce[T, S any](name string, init S, f func(context.Context, S, T) (S, error)) *AggregateNode[T, S] {
+	state := init
+	return NewAggregate[T, S](name, 0, AggregateFunc(
+		func(ctx context.Context, val T, emit Emit[S]) error {
+			s, err := f(ctx, state, val)
+			if err != nil {
+				return err
+			}
+			state = s
+			return nil
+		},
+		func(ctx context.Context, emit Emit[S]) error {
+			return emit(state)
+		},
+	))
+}
+
+// NewTake creates a node that emits the first n values and then finishes, which completes a finite stream.
+func NewTake[T any](name string, n int) *FlatMapNode[T, T] {
+	count := 0
+	return NewFlatMap[T, T](name, FlatMapFunc(func(ctx context.Context, val T, emit Emit[T]) error {
+		if count >= n {
+			// The node does not release the value when finishing with ErrStop.
+			return errors.Join(graco.Release(val), ErrStop)
+		}
+		count++
+		if err := emit(val); err != nil {
+			// emit released the value, ErrStop keeps the node from releasing it again.
+			// The graph still fails unless the send failed because the output is disconnected (io.EOF).
+			return errors.Join(err, ErrStop)
+		}
+		if count >= n {
+			return ErrStop
+		}
+		return nil
+	}))
+}
+
+// NewSkip creates a node that releases the first n values and emits the rest.
+func NewSkip[T any](name string, n int) *Node[T, T] {
+	count := 0
+	return New[T, T](name, Func(func(ctx context.Context, val T) (T, error) {
+		if count < n {
+			count++
+			return val, ErrDrop
+		}
+		return val, nil
+	}))
+}
+
+// NewTakeWhile creates a node that emits values while pred returns true.
+// The first value for which pred returns false is released and the node finishes.
+func NewTakeWhile[T any](name string, pred func(context.Context, T) (bool, error)) *Node[T, T] {
+	return New[T, T](name, Func(func(ctx context.Context, val T) (T, error) {
+		ok, err := pred(ctx, val)
+		if err == nil && !ok {
+			err = ErrStop
+		}
+		return val, err
+	}))
+}
+
+// NewDistinct creates a node that emits only the first occurrence of every value. Duplicates are released.
+// Every distinct value is remembered, therefore memory grows with the number of distinct values.
+func NewDistinct[T comparable](name string) *Node[T, T] {
+	seen := make(map[T]struct{})
+	return New[T, T](name, Func(func(ctx context.Context, val T) (T, error) {
+		if _, ok := seen[val]; ok {
+			return val, ErrDrop
+		}
+		seen[val] = struct{}{}
+		return val, nil
+	}))
+}
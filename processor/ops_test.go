@@ -0,0 +1,67 @@
+package processor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/itohio/graco"
+	"github.com/itohio/graco/processor"
+	"github.com/itohio/graco/sink"
+	"github.com/itohio/graco/source"
+)
+
+func TestTake(t *testing.T) {
+	for _, n := range []int{-1, 0, 1, 5} {
+		d := graco.EnableLeakDetection()
+
+		i := 0
+		src := source.New[*graco.Shared[int]]("src", source.Func[*graco.Shared[int]](func(ctx context.Context) (*graco.Shared[int], error) {
+			i++
+			return graco.NewShared(i, nil), nil
+		}))
+		take := processor.NewTake[*graco.Shared[int]]("take", n)
+		var got int
+		snk := sink.NewFunc[*graco.Shared[int]]("sink", sink.Func[*graco.Shared[int]](func(ctx context.Context, val *graco.Shared[int]) error {
+			got++
+			return val.Close()
+		}))
+
+		o1, err := src.Connect()
+		if err != nil {
+			t.Fatal(err)
+		}
+		o2, err := take.Connect(o1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := snk.Connect(o2); err != nil {
+			t.Fatal(err)
+		}
+
+		g := graco.New()
+		g.AddNode(0, src, take, snk)
+		g.AddEdge(0, o1, o2)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err = g.Start(ctx)
+		cancel()
+		if err != nil {
+			t.Errorf("n=%d: %v", n, err)
+		}
+		if err := g.Close(); err != nil {
+			t.Errorf("n=%d: close: %v", n, err)
+		}
+
+		want := n
+		if want < 0 {
+			want = 0
+		}
+		if got != want {
+			t.Errorf("n=%d: got %d values, want %d", n, got, want)
+		}
+		if err := d.Check(); err != nil {
+			t.Errorf("n=%d: %v", n, err)
+		}
+		graco.DisableLeakDetection()
+	}
+}
@@ -0,0 +1,225 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/itohio/graco"
+)
+
+// ParallelNode processes values on several workers and emits results in input order.
+// Results that are ready early wait in a reorder buffer until all preceding results are emitted.
+// The processor must be safe for concurrent use.
+type ParallelNode[Tin, To any] struct {
+	graco.NodeBase
+	name     string
+	workers  int
+	inFlight int
+	input    graco.SourceEdge[Tin]
+	output   graco.SourceEdge[To]
+	process  ProcessCloser[Tin, To]
+	expired  atomic.Uint64
+}
+
+type parallelResult[Tin, To any] struct {
+	val Tin
+	res To
+	err error
+}
+
+type parallelJob[Tin any] struct {
+	slot int
+	val  Tin
+}
+
+// NewParallel creates an ordered parallel processor node.
+// workers: Number of workers. Non-positive value uses GOMAXPROCS.
+// maxInFlight: Maximum number of values being processed or waiting to be emitted. It is at least workers and defaults to 2*workers.
+func NewParallel[Tin, To any](name string, workers, maxInFlight int, processor ProcessCloser[Tin, To]) *ParallelNode[Tin, To] {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if maxInFlight <= 0 {
+		maxInFlight = 2 * workers
+	}
+	if maxInFlight < workers {
+		maxInFlight = workers
+	}
+	res := &ParallelNode[Tin, To]{
+		name:     name,
+		workers:  workers,
+		inFlight: maxInFlight,
+		process:  processor,
+	}
+	return res
+}
+
+func (n *ParallelNode[T, To]) Close() error {
+	return n.CloseOnce(func() error {
+		var err error
+		if n.process != nil {
+			err = n.process.Close()
+		}
+		return errors.Join(err, n.CloseOutputs(n.output))
+	})
+}
+func (n *ParallelNode[T, To]) Name() string { return n.name }
+
+// Expired returns the number of expired values discarded by the node.
+func (n *ParallelNode[T, To]) Expired() uint64 { return n.expired.Load() }
+
+func (n *ParallelNode[T, To]) Connect(in graco.SourceEdge[T]) (graco.SourceEdge[To], error) {
+	n.input = in
+	err := in.Connect(n)
+	if err != nil {
+		return nil, err
+	}
+	n.output, err = graco.NewSourceEdge[To]("o", n, 1, false)
+	return n.output, err
+}
+
+func (n *ParallelNode[T, To]) Start(pctx context.Context) error {
+	defer n.CloseOutputs(n.output)
+	if err := graco.IsEdgeValid(n.input); err != nil {
+		return err
+	}
+	if err := graco.IsEdgeValid(n.output); err != nil {
+		return err
+	}
+
+	if n.process == nil {
+		return errors.New("processor nil")
+	}
+
+	ctx, cancel := context.WithCancelCause(pctx)
+	defer cancel(nil)
+
+	// Each in-flight value owns a slot until its result is emitted. Slots are emitted in the order they are queued.
+	slots := make([]chan parallelResult[T, To], n.inFlight)
+	for i := range slots {
+		slots[i] = make(chan parallelResult[T, To], 1)
+	}
+	var (
+		sem   = make(chan struct{}, n.inFlight)
+		order = make(chan int, n.inFlight)
+		jobs  = make(chan parallelJob[T], n.workers)
+		wg    sync.WaitGroup
+		inErr error
+	)
+
+	for i := 0; i < n.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n.work(ctx, jobs, slots)
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(order)
+		defer close(jobs)
+		inErr = n.dispatch(ctx, jobs, order, sem)
+	}()
+
+	err := n.emit(ctx, order, slots, sem)
+	cancel(err)
+	wg.Wait()
+
+	// Release values left in the reorder buffer after a failure.
+	for slot := range order {
+		r := <-slots[slot]
+		if r.err != nil {
+			err = errors.Join(err, graco.Release(r.val))
+		} else {
+			err = errors.Join(err, graco.Release(r.res))
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return inErr
+}
+
+// dispatch queues input values to the workers, reserving a slot for each of them.
+func (n *ParallelNode[T, To]) dispatch(ctx context.Context, jobs chan<- parallelJob[T], order chan<- int, sem chan struct{}) error {
+	for seq := 0; ; seq++ {
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case sem <- struct{}{}:
+		}
+
+		val, err := n.recv(ctx)
+		if err != nil {
+			return err
+		}
+
+		job := parallelJob[T]{slot: seq % n.inFlight, val: val}
+		select {
+		case <-ctx.Done():
+			return errors.Join(context.Cause(ctx), graco.Release(val))
+		case jobs <- job:
+		}
+		order <- job.slot
+	}
+}
+
+// recv receives the next input value that has not expired.
+func (n *ParallelNode[T, To]) recv(ctx context.Context) (T, error) {
+	for {
+		val, err := n.input.Recv(ctx)
+		if err != nil {
+			return val, err
+		}
+		expired, err := graco.DropExpired(val)
+		if !expired {
+			return val, nil
+		}
+		n.expired.Add(1)
+		if err != nil {
+			return val, err
+		}
+	}
+}
+
+// work processes queued values. A result is always delivered, even if the context is canceled.
+func (n *ParallelNode[T, To]) work(ctx context.Context, jobs <-chan parallelJob[T], slots []chan parallelResult[T, To]) {
+	for job := range jobs {
+		r := parallelResult[T, To]{val: job.val}
+		if r.err = ctx.Err(); r.err != nil {
+			r.err = context.Cause(ctx)
+		} else {
+			r.res, r.err = n.process.Process(ctx, job.val)
+		}
+		slots[job.slot] <- r
+	}
+}
+
+// emit sends results in input order until the dispatcher finishes or a value fails.
+func (n *ParallelNode[T, To]) emit(ctx context.Context, order <-chan int, slots []chan parallelResult[T, To], sem chan struct{}) error {
+	for slot := range order {
+		r := <-slots[slot]
+		<-sem
+
+		if errors.Is(r.err, ErrDrop) {
+			if err := graco.Release(r.val); err != nil {
+				return err
+			}
+			continue
+		}
+		if r.err != nil {
+			return errors.Join(r.err, graco.Release(r.val))
+		}
+		graco.Untrack(r.val)
+		graco.Track(r.res)
+
+		if err := n.output.Send(ctx, r.res); err != nil {
+			return errors.Join(err, graco.Release(r.res))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package processor
+
+import (
+	"math/bits"
+	"sync"
+)
+
+const (
+	defaultMinBlobSize = 64
+	defaultMaxBlobSize = 1 << 20
+)
+
+// BlobPool is an allocator of byte buffers grouped in power of two size classes.
+// Buffers are stored as pointers so that putting them back does not allocate.
+// Buffers larger than the largest class are not pooled.
+type BlobPool struct {
+	minShift int
+	classes  []sync.Pool
+}
+
+// NewBlobPool creates a pool with size classes from min to max bytes, both rounded up to a power of two.
+// Non-positive values select defaults of 64 bytes and 1 MiB.
+func NewBlobPool(min, max int) *BlobPool {
+	if min <= 0 {
+		min = defaultMinBlobSize
+	}
+	if max <= 0 {
+		max = defaultMaxBlobSize
+	}
+	if max < min {
+		max = min
+	}
+	minShift := bits.Len(uint(min - 1))
+	maxShift := bits.Len(uint(max - 1))
+	return &BlobPool{
+		minShift: minShift,
+		classes:  make([]sync.Pool, maxShift-minShift+1),
+	}
+}
+
+// Get returns an empty buffer with a capacity of at least n bytes.
+func (p *BlobPool) Get(n int) *[]byte {
+	class := p.class(n)
+	if class >= len(p.classes) {
+		buf := make([]byte, 0, n)
+		return &buf
+	}
+	if buf, ok := p.classes[class].Get().(*[]byte); ok {
+		*buf = (*buf)[:0]
+		return buf
+	}
+	buf := make([]byte, 0, 1<<(class+p.minShift))
+	return &buf
+}
+
+// Put returns a buffer to the pool. The buffer must not be used afterwards.
+func (p *BlobPool) Put(buf *[]byte) {
+	if buf == nil {
+		return
+	}
+	c := cap(*buf)
+	if c < 1<<p.minShift {
+		return
+	}
+	// A buffer belongs to the largest class it can fully serve.
+	class := bits.Len(uint(c)) - 1 - p.minShift
+	if class >= len(p.classes) {
+		return
+	}
+	p.classes[class].Put(buf)
+}
+
+// class returns the index of the smallest class that fits n bytes.
+func (p *BlobPool) class(n int) int {
+	if n <= 1<<p.minShift {
+		return 0
+	}
+	return bits.Len(uint(n-1)) - p.minShift
+}
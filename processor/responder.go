@@ -0,0 +1,103 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/itohio/graco"
+)
+
+// Responder is a node that consumes correlated requests, processes them and writes the results to the reply edge.
+// Processing errors are sent back to the requester instead of stopping the node, except for ErrStop.
+type Responder[Treq, Tresp any] struct {
+	graco.NodeBase
+	name    string
+	input   graco.RequestEdge[Treq, Tresp]
+	reply   graco.SourceEdge[graco.Response[Tresp]]
+	process ProcessCloser[Treq, Tresp]
+	timeout time.Duration
+}
+
+// NewResponder creates a Responder node.
+// timeout limits processing time of each request in addition to the deadline carried by the request. Zero means no limit.
+func NewResponder[Treq, Tresp any](name string, timeout time.Duration, processor ProcessCloser[Treq, Tresp]) *Responder[Treq, Tresp] {
+	res := &Responder[Treq, Tresp]{
+		name:    name,
+		process: processor,
+		timeout: timeout,
+	}
+	return res
+}
+
+func (n *Responder[Treq, Tresp]) Close() error {
+	return n.CloseOnce(func() error {
+		var err error
+		if n.process != nil {
+			err = n.process.Close()
+		}
+		return errors.Join(err, n.CloseOutputs(n.reply))
+	})
+}
+func (n *Responder[Treq, Tresp]) Name() string { return n.name }
+
+func (n *Responder[Treq, Tresp]) Connect(in graco.RequestEdge[Treq, Tresp]) error {
+	n.input = in
+	err := in.Connect(n)
+	if err != nil {
+		return err
+	}
+	n.reply, err = in.Reply()
+	return err
+}
+
+func (n *Responder[Treq, Tresp]) Start(ctx context.Context) error {
+	defer n.CloseOutputs(n.reply)
+	if err := graco.IsEdgeValid(n.input); err != nil {
+		return err
+	}
+	if err := graco.IsEdgeValid(n.reply); err != nil {
+		return err
+	}
+
+	if n.process == nil {
+		return errors.New("processor nil")
+	}
+
+	for {
+		req, err := n.input.Recv(ctx)
+		if err != nil {
+			return err
+		}
+
+		resp := graco.Response[Tresp]{ID: req.ID}
+		resp.Val, resp.Err = n.call(ctx, req)
+		if errors.Is(resp.Err, ErrStop) {
+			return resp.Err
+		}
+
+		if err := n.reply.Send(ctx, resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (n *Responder[Treq, Tresp]) call(ctx context.Context, req graco.Request[Treq]) (Tresp, error) {
+	deadline := req.Deadline
+	if n.timeout > 0 {
+		if d := time.Now().Add(n.timeout); deadline.IsZero() || d.Before(deadline) {
+			deadline = d
+		}
+	}
+	if deadline.IsZero() {
+		return n.process.Process(ctx, req.Val)
+	}
+
+	rctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	if err := rctx.Err(); err != nil {
+		var zero Tresp
+		return zero, err
+	}
+	return n.process.Process(rctx, req.Val)
+}
@@ -0,0 +1,92 @@
+package graco
+
+import (
+	"errors"
+	"io"
+	"reflect"
+)
+
+var (
+	_ Drainer = (*ChannelSourceEdge[int])(nil)
+	_ Drainer = (*RingEdge[int])(nil)
+)
+
+// Drainer is an interface implemented by edges that can release buffered values on shutdown.
+type Drainer interface {
+	// Drain discards buffered values releasing them. It must be called after the source node has finished.
+	Drain() error
+}
+
+// Release releases a value that will never reach a consumer, e.g. because it was dropped or left in an edge buffer.
+// Values implementing io.Closer are closed. Every value must be released or consumed exactly once.
+func Release(val any) error {
+	closer, ok := val.(io.Closer)
+	if !ok {
+		return nil
+	}
+	Untrack(val)
+	return closer.Close()
+}
+
+// Track registers a value implementing io.Closer with the leak detector, if enabled.
+// Built-in source nodes track the values they produce. Reference counted values track themselves.
+func Track(val any) {
+	d := leakDetector.Load()
+	if d == nil || !trackable(val) {
+		return
+	}
+	d.track(val)
+}
+
+// Untrack unregisters a value from the leak detector, if enabled. Built-in nodes untrack values they consume.
+func Untrack(val any) {
+	d := leakDetector.Load()
+	if d == nil || !trackable(val) {
+		return
+	}
+	d.untrack(val)
+}
+
+// trackable reports whether a value is a closer with pointer identity that does not track itself.
+func trackable(val any) bool {
+	if _, ok := val.(io.Closer); !ok {
+		return false
+	}
+	if _, ok := val.(Retainer); ok {
+		return false
+	}
+	return reflect.ValueOf(val).Kind() == reflect.Pointer
+}
+
+// Drain releases values buffered in the channel without blocking.
+func (e *ChannelSourceEdge[T]) Drain() error {
+	var err error
+	for {
+		select {
+		case val, ok := <-e.ch:
+			if !ok {
+				return err
+			}
+			err = errors.Join(err, Release(val))
+		default:
+			return err
+		}
+	}
+}
+
+// Drain releases values buffered in the ring.
+func (e *RingEdge[T]) Drain() error {
+	var (
+		err  error
+		zero T
+	)
+	head := e.head.Load()
+	tail := e.tail.Load()
+	for ; head != tail; head++ {
+		idx := head & e.mask
+		err = errors.Join(err, Release(e.buf[idx]))
+		e.buf[idx] = zero
+	}
+	e.consume(head)
+	return err
+}
@@ -0,0 +1,70 @@
+// Package remote provides edges that split a graph across processes over TCP or Unix domain sockets.
+//
+// SinkEdge is the output edge of a node in the producing process. It dials the peer and writes framed values.
+// SourceEdge is the input edge of a node in the consuming process. It listens for connections and yields received values.
+// Both implement graco.EdgeStarter so that graco.ConcurrentGraph manages their connections.
+// Frames are limited to DefaultMaxFrameSize unless both ends are configured with SetMaxFrameSize.
+package remote
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	frameData      byte = 1
+	frameHeartbeat byte = 2
+
+	frameHeaderSize = 5
+)
+
+// DefaultMaxFrameSize is the default limit of the size of an encoded value.
+const DefaultMaxFrameSize = 4 << 20
+
+var ErrFrameTooLarge = errors.New("frame too large")
+
+// peer is a placeholder node representing the remote end of a connection.
+type peer struct {
+	network, addr string
+}
+
+func (p peer) Close() error                    { return nil }
+func (p peer) Name() string                    { return fmt.Sprintf("%s://%s", p.network, p.addr) }
+func (p peer) Start(ctx context.Context) error { return nil }
+
+func writeFrame(w io.Writer, typ byte, payload []byte) error {
+	buf := make([]byte, frameHeaderSize+len(payload))
+	buf[0] = typ
+	binary.BigEndian.PutUint32(buf[1:], uint32(len(payload)))
+	copy(buf[frameHeaderSize:], payload)
+	_, err := w.Write(buf)
+	return err
+}
+
+// maxFrame returns the frame size limit, selecting the default for non-positive n.
+func maxFrame(n int) int {
+	if n <= 0 {
+		return DefaultMaxFrameSize
+	}
+	return n
+}
+
+// readFrame reads a frame whose payload is at most max bytes.
+func readFrame(r io.Reader, max int) (byte, []byte, error) {
+	var header [frameHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if uint64(size) > uint64(max) {
+		return 0, nil, ErrFrameTooLarge
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return header[0], payload, nil
+}
@@ -0,0 +1,272 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/itohio/graco"
+	"github.com/itohio/graco/codec"
+	"github.com/itohio/graco/sink"
+)
+
+var (
+	_ graco.SourceEdge[int] = (*SinkEdge[int])(nil)
+	_ graco.EdgeStarter     = (*SinkEdge[int])(nil)
+)
+
+var errClosed = errors.New("edge closed")
+
+const (
+	minBackoff = 100 * time.Millisecond
+	maxBackoff = 5 * time.Second
+)
+
+// SinkEdge is an output edge that sends values to a SourceEdge in another process.
+// It is typically used as the sinker of a sink.Node that acts as its source node.
+// Send blocks while disconnected and while the peer applies backpressure, and retries the value after a reconnect.
+// Heartbeats are sent while the connection is idle.
+type SinkEdge[T any] struct {
+	name      string
+	src, dst  graco.Node
+	network   string
+	addr      string
+	codec     codec.Codec[T]
+	heartbeat time.Duration
+	maxFrame  int
+
+	writeMu   sync.Mutex
+	lastWrite time.Time
+
+	mu     sync.Mutex
+	conn   net.Conn
+	ready  chan struct{} // closed when conn is established
+	broken chan struct{} // signaled when conn fails
+	done   chan struct{} // closed by Close
+	closed bool
+}
+
+// NewSinkEdge creates a SinkEdge that dials addr on network ("tcp", "unix", ...).
+// heartbeat: Interval of heartbeats on an idle connection. Zero disables heartbeats.
+func NewSinkEdge[T any](name string, src graco.Node, network, addr string, c codec.Codec[T], heartbeat time.Duration) *SinkEdge[T] {
+	res := &SinkEdge[T]{
+		name:      name,
+		src:       src,
+		dst:       peer{network: network, addr: addr},
+		network:   network,
+		addr:      addr,
+		codec:     c,
+		heartbeat: heartbeat,
+		maxFrame:  DefaultMaxFrameSize,
+		ready:     make(chan struct{}),
+		broken:    make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+	return res
+}
+
+// NewSink creates a sink node that sends its input to a peer process over a SinkEdge.
+// Both the node and the edge must be added to the graph.
+func NewSink[T any](name, network, addr string, c codec.Codec[T], heartbeat time.Duration) (*sink.Node[T], *SinkEdge[T]) {
+	e := NewSinkEdge[T](name, nil, network, addr, c, heartbeat)
+	n := sink.NewFunc[T](name, e)
+	e.src = n
+	return n, e
+}
+
+// SetMaxFrameSize sets the limit of the size of an encoded value. Non-positive n selects DefaultMaxFrameSize.
+// The limit must not exceed the one of the peer and must be set before the edge is started.
+func (e *SinkEdge[T]) SetMaxFrameSize(n int) { e.maxFrame = maxFrame(n) }
+
+func (e *SinkEdge[T]) Name() string                    { return e.name }
+func (e *SinkEdge[T]) Nodes() (graco.Node, graco.Node) { return e.src, e.dst }
+
+// Connect is a no-op since the destination node is in another process.
+func (e *SinkEdge[T]) Connect(graco.Node) error { return nil }
+func (e *SinkEdge[T]) C() chan T                { return nil }
+
+func (e *SinkEdge[T]) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	close(e.done)
+	if e.conn == nil {
+		return nil
+	}
+	err := e.conn.Close()
+	e.conn = nil
+	return err
+}
+
+// Start maintains the connection, reconnecting with exponential backoff, until the context is canceled or the edge is closed.
+func (e *SinkEdge[T]) Start(ctx context.Context) error {
+	var dialer net.Dialer
+	backoff := minBackoff
+	for {
+		conn, err := dialer.DialContext(ctx, e.network, e.addr)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return context.Cause(ctx)
+			case <-e.done:
+				return nil
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			continue
+		}
+		backoff = minBackoff
+
+		e.mu.Lock()
+		if e.closed {
+			e.mu.Unlock()
+			conn.Close()
+			return nil
+		}
+		select {
+		case <-e.broken:
+		default:
+		}
+		e.conn = conn
+		close(e.ready)
+		e.mu.Unlock()
+
+		// The peer never writes, so a finished read means the connection is gone.
+		go func() {
+			io.Copy(io.Discard, conn)
+			e.fail(conn)
+		}()
+
+		err = e.keepalive(ctx, conn)
+		e.fail(conn)
+		if errors.Is(err, errClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (e *SinkEdge[T]) Send(ctx context.Context, val T) error {
+	data, err := e.codec.Marshal(val)
+	if err != nil {
+		return err
+	}
+	// The peer would drop the connection on every retry.
+	if len(data) > e.maxFrame {
+		return ErrFrameTooLarge
+	}
+	for {
+		conn, err := e.wait(ctx)
+		if err != nil {
+			return err
+		}
+		if err := e.write(conn, frameData, data); err == nil {
+			return nil
+		}
+		e.fail(conn)
+	}
+}
+
+// Sink sends a value. It allows the edge to be used as a sink.SinkCloser of the producing sink.Node.
+func (e *SinkEdge[T]) Sink(ctx context.Context, val T) error {
+	return e.Send(ctx, val)
+}
+
+// Recv always fails since values are received by the peer process.
+func (e *SinkEdge[T]) Recv(ctx context.Context) (T, error) {
+	var zero T
+	return zero, errors.New("remote sink edge cannot receive")
+}
+
+// keepalive sends heartbeats on an idle connection until it breaks.
+// Returns nil if the connection broke and an error if the edge must stop.
+func (e *SinkEdge[T]) keepalive(ctx context.Context, conn net.Conn) error {
+	var tick <-chan time.Time
+	if e.heartbeat > 0 {
+		ticker := time.NewTicker(e.heartbeat)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-e.done:
+			return errClosed
+		case <-e.broken:
+			return nil
+		case <-tick:
+		}
+
+		if !e.writeMu.TryLock() {
+			// A write is in progress, possibly blocked by backpressure.
+			continue
+		}
+		var err error
+		if time.Since(e.lastWrite) >= e.heartbeat {
+			err = writeFrame(conn, frameHeartbeat, nil)
+			e.lastWrite = time.Now()
+		}
+		e.writeMu.Unlock()
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// wait returns the current connection waiting for it to be established.
+func (e *SinkEdge[T]) wait(ctx context.Context) (net.Conn, error) {
+	for {
+		e.mu.Lock()
+		if e.closed {
+			e.mu.Unlock()
+			return nil, errClosed
+		}
+		conn, ready := e.conn, e.ready
+		e.mu.Unlock()
+		if conn != nil {
+			return conn, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		case <-e.done:
+		case <-ready:
+		}
+	}
+}
+
+func (e *SinkEdge[T]) write(conn net.Conn, typ byte, data []byte) error {
+	e.writeMu.Lock()
+	defer e.writeMu.Unlock()
+	e.lastWrite = time.Now()
+	return writeFrame(conn, typ, data)
+}
+
+// fail drops a broken connection and notifies Start to reconnect.
+func (e *SinkEdge[T]) fail(conn net.Conn) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.conn != conn {
+		return
+	}
+	conn.Close()
+	e.conn = nil
+	e.ready = make(chan struct{})
+	select {
+	case e.broken <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,205 @@
+package remote
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/itohio/graco"
+	"github.com/itohio/graco/codec"
+)
+
+var (
+	_ graco.SourceEdge[int] = (*SourceEdge[int])(nil)
+	_ graco.EdgeStarter     = (*SourceEdge[int])(nil)
+)
+
+// SourceEdge is an input edge that receives values sent by SinkEdge in another process.
+// Values are read from the connection only when there is room in the buffer, which propagates backpressure to the sender.
+// A connection that stays silent for three heartbeat intervals is dropped.
+type SourceEdge[T any] struct {
+	name      string
+	src, dst  graco.Node
+	network   string
+	addr      string
+	codec     codec.Codec[T]
+	heartbeat time.Duration
+	maxFrame  int
+	ch        chan T
+	done      chan struct{}
+	closeOnce sync.Once
+	chOnce    sync.Once
+
+	mu      sync.Mutex
+	started bool
+}
+
+// NewSourceEdge creates a SourceEdge that listens on addr on network ("tcp", "unix", ...).
+// cap: Capacity of the receive buffer.
+// heartbeat: Expected heartbeat interval of the sender. Zero disables idle connection detection.
+func NewSourceEdge[T any](name, network, addr string, c codec.Codec[T], cap int, heartbeat time.Duration) *SourceEdge[T] {
+	if cap < 0 {
+		cap = 0
+	}
+	res := &SourceEdge[T]{
+		name:      name,
+		src:       peer{network: network, addr: addr},
+		network:   network,
+		addr:      addr,
+		codec:     c,
+		heartbeat: heartbeat,
+		maxFrame:  DefaultMaxFrameSize,
+		ch:        make(chan T, cap),
+		done:      make(chan struct{}),
+	}
+	return res
+}
+
+// SetMaxFrameSize sets the limit of the size of a received value. Non-positive n selects DefaultMaxFrameSize.
+// Larger frames drop the connection. The limit must be set before the edge is started.
+func (e *SourceEdge[T]) SetMaxFrameSize(n int) { e.maxFrame = maxFrame(n) }
+
+func (e *SourceEdge[T]) Name() string                    { return e.name }
+func (e *SourceEdge[T]) Nodes() (graco.Node, graco.Node) { return e.src, e.dst }
+func (e *SourceEdge[T]) Connect(dst graco.Node) error {
+	e.dst = dst
+	return nil
+}
+func (e *SourceEdge[T]) C() chan T { return e.ch }
+
+// Close stops listening. Buffered values can still be received, after which Recv returns io.EOF.
+func (e *SourceEdge[T]) Close() error {
+	e.closeOnce.Do(func() { close(e.done) })
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.started {
+		e.chOnce.Do(func() { close(e.ch) })
+	}
+	return nil
+}
+
+// Start listens for connections and receives values until the context is canceled or the edge is closed.
+func (e *SourceEdge[T]) Start(pctx context.Context) error {
+	e.mu.Lock()
+	select {
+	case <-e.done:
+		e.mu.Unlock()
+		return nil
+	default:
+	}
+	e.started = true
+	e.mu.Unlock()
+	defer e.chOnce.Do(func() { close(e.ch) })
+
+	ctx, cancel := context.WithCancel(pctx)
+	defer cancel()
+
+	if e.network == "unix" {
+		if err := os.Remove(e.addr); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	var lc net.ListenConfig
+	ln, err := lc.Listen(ctx, e.network, e.addr)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-e.done:
+		}
+		ln.Close()
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			select {
+			case <-e.done:
+				return nil
+			default:
+			}
+			if ctx.Err() != nil {
+				return context.Cause(pctx)
+			}
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.serve(ctx, conn)
+		}()
+	}
+}
+
+func (e *SourceEdge[T]) Send(ctx context.Context, val T) error {
+	return errors.New("remote source edge cannot send")
+}
+
+func (e *SourceEdge[T]) Recv(ctx context.Context) (T, error) {
+	var zero T
+	if e.dst == nil {
+		return zero, errors.New("input disconnected")
+	}
+
+	select {
+	case <-ctx.Done():
+		return zero, context.Cause(ctx)
+	case c, ok := <-e.ch:
+		if !ok {
+			return zero, io.EOF
+		}
+		return c, nil
+	}
+}
+
+// serve reads frames from a connection until it fails, the context is canceled or the edge is closed.
+func (e *SourceEdge[T]) serve(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-e.done:
+		case <-stop:
+		}
+		conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	for {
+		if e.heartbeat > 0 {
+			conn.SetReadDeadline(time.Now().Add(3 * e.heartbeat))
+		}
+		typ, payload, err := readFrame(r, e.maxFrame)
+		if err != nil {
+			return
+		}
+		if typ != frameData {
+			continue
+		}
+
+		val, err := e.codec.Unmarshal(payload)
+		if err != nil {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.done:
+			return
+		case e.ch <- val:
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package graco
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	_ SourceEdge[int] = (*RingEdge[int])(nil)
+	_ BatchEdge[int]  = (*RingEdge[int])(nil)
+	_ Queue           = (*RingEdge[int])(nil)
+	_ Disconnecter    = (*RingEdge[int])(nil)
+)
+
+// ringSpin is the number of times a RingEdge yields before parking a waiting goroutine.
+const ringSpin = 64
+
+type cacheLinePad [64]byte
+
+// RingEdge is a single-producer/single-consumer lock-free ring buffer implementation of the SourceEdge[T] interface.
+// It avoids the channel overhead per hop and supports batch transfers via SendN and RecvN.
+// Waiting sides spin for a short while before parking.
+//
+// RingEdge is not channel backed, therefore C returns nil and nodes that require C (e.g. throttle.DropNode) must not be used with it.
+type RingEdge[T any] struct {
+	name     string
+	src, dst Node
+	buf      []T
+	mask     uint64
+
+	_    cacheLinePad
+	head atomic.Uint64 // next read position, advanced by the consumer
+	_    cacheLinePad
+	tail atomic.Uint64 // next write position, advanced by the producer
+	_    cacheLinePad
+
+	closed   atomic.Bool
+	gone     atomic.Bool
+	recvWait atomic.Bool
+	sendWait atomic.Bool
+	notEmpty chan struct{}
+	notFull  chan struct{}
+}
+
+// NewRingEdge creates a RingEdge[T] instance that satisfies SourceEdge[T] interface.
+// cap: Capacity of the ring buffer. It is rounded up to the next power of two.
+func NewRingEdge[T any](name string, src Node, cap int) (*RingEdge[T], error) {
+	size := 1
+	for size < cap {
+		size <<= 1
+	}
+	res := &RingEdge[T]{
+		name:     name,
+		src:      src,
+		buf:      make([]T, size),
+		mask:     uint64(size - 1),
+		notEmpty: make(chan struct{}, 1),
+		notFull:  make(chan struct{}, 1),
+	}
+	return res, nil
+}
+
+func (e *RingEdge[T]) Name() string        { return e.name }
+func (e *RingEdge[T]) Nodes() (Node, Node) { return e.src, e.dst }
+func (e *RingEdge[T]) Connect(dst Node) error {
+	e.dst = dst
+	return nil
+}
+func (e *RingEdge[T]) C() chan T { return nil }
+func (e *RingEdge[T]) Close() error {
+	if e.closed.Swap(true) {
+		return nil
+	}
+	signal(e.notEmpty)
+	signal(e.notFull)
+	return nil
+}
+
+// Disconnect makes further sends return io.EOF and releases buffered values.
+// It is called by the graph when the destination node finishes, after which it acts as the consumer.
+func (e *RingEdge[T]) Disconnect() error {
+	if e.gone.Swap(true) {
+		return nil
+	}
+	signal(e.notFull)
+	return e.Drain()
+}
+
+// Len returns the number of values buffered in the ring.
+func (e *RingEdge[T]) Len() int { return int(e.tail.Load() - e.head.Load()) }
+
+// Cap returns the capacity of the ring.
+func (e *RingEdge[T]) Cap() int { return len(e.buf) }
+
+func (e *RingEdge[T]) Send(ctx context.Context, val T) error {
+	if e.dst == nil {
+		return errors.New("output disconnected")
+	}
+
+	tail := e.tail.Load()
+	if _, err := e.waitFree(ctx, tail); err != nil {
+		return err
+	}
+	e.buf[tail&e.mask] = val
+	e.publish(tail + 1)
+	return nil
+}
+
+// SendN sends all values from vals over the edge, blocking while the ring is full.
+// Returns the number of values sent, which is less than len(vals) only if an error occurred.
+func (e *RingEdge[T]) SendN(ctx context.Context, vals []T) (int, error) {
+	if e.dst == nil {
+		return 0, errors.New("output disconnected")
+	}
+
+	sent := 0
+	for sent < len(vals) {
+		tail := e.tail.Load()
+		free, err := e.waitFree(ctx, tail)
+		if err != nil {
+			return sent, err
+		}
+		n := len(vals) - sent
+		if uint64(n) > free {
+			n = int(free)
+		}
+		for i := 0; i < n; i++ {
+			e.buf[(tail+uint64(i))&e.mask] = vals[sent+i]
+		}
+		e.publish(tail + uint64(n))
+		sent += n
+	}
+	return sent, nil
+}
+
+func (e *RingEdge[T]) Recv(ctx context.Context) (T, error) {
+	var zero T
+	if e.src == nil {
+		return zero, errors.New("input disconnected")
+	}
+
+	head := e.head.Load()
+	if _, err := e.waitData(ctx, head); err != nil {
+		return zero, err
+	}
+	idx := head & e.mask
+	val := e.buf[idx]
+	e.buf[idx] = zero
+	e.consume(head + 1)
+	return val, nil
+}
+
+// RecvN blocks until at least one value is available and then receives up to len(dst) values without further blocking.
+// Returns the number of values written to dst.
+func (e *RingEdge[T]) RecvN(ctx context.Context, dst []T) (int, error) {
+	if e.src == nil {
+		return 0, errors.New("input disconnected")
+	}
+	if len(dst) == 0 {
+		return 0, nil
+	}
+
+	head := e.head.Load()
+	avail, err := e.waitData(ctx, head)
+	if err != nil {
+		return 0, err
+	}
+	n := len(dst)
+	if uint64(n) > avail {
+		n = int(avail)
+	}
+	var zero T
+	for i := 0; i < n; i++ {
+		idx := (head + uint64(i)) & e.mask
+		dst[i] = e.buf[idx]
+		e.buf[idx] = zero
+	}
+	e.consume(head + uint64(n))
+	return n, nil
+}
+
+func (e *RingEdge[T]) SendBatch(ctx context.Context, vals []T) (int, error) {
+	return e.SendN(ctx, vals)
+}
+
+func (e *RingEdge[T]) RecvBatch(ctx context.Context, max int, linger time.Duration) ([]T, error) {
+	if max < 1 {
+		max = 1
+	}
+	res := make([]T, max)
+	n, err := e.RecvN(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+	if n >= max || linger <= 0 {
+		return res[:n], nil
+	}
+
+	lctx, cancel := context.WithTimeout(ctx, linger)
+	defer cancel()
+	for n < max {
+		m, err := e.RecvN(lctx, res[n:])
+		if err != nil {
+			break
+		}
+		n += m
+	}
+	return res[:n], nil
+}
+
+// publish makes values up to tail visible to the consumer and wakes it if parked.
+func (e *RingEdge[T]) publish(tail uint64) {
+	e.tail.Store(tail)
+	if e.recvWait.CompareAndSwap(true, false) {
+		signal(e.notEmpty)
+	}
+}
+
+// consume releases slots up to head to the producer and wakes it if parked.
+func (e *RingEdge[T]) consume(head uint64) {
+	e.head.Store(head)
+	if e.sendWait.CompareAndSwap(true, false) {
+		signal(e.notFull)
+	}
+}
+
+// waitFree waits until there is at least one free slot and returns the number of free slots.
+func (e *RingEdge[T]) waitFree(ctx context.Context, tail uint64) (uint64, error) {
+	size := uint64(len(e.buf))
+	for spin := 0; ; spin++ {
+		if e.closed.Load() {
+			return 0, errors.New("edge closed")
+		}
+		if e.gone.Load() {
+			return 0, io.EOF
+		}
+		if free := size - (tail - e.head.Load()); free > 0 {
+			return free, nil
+		}
+		if spin < ringSpin {
+			runtime.Gosched()
+			continue
+		}
+
+		e.sendWait.Store(true)
+		if free := size - (tail - e.head.Load()); free > 0 {
+			return free, nil
+		}
+		select {
+		case <-ctx.Done():
+			return 0, context.Cause(ctx)
+		case <-e.notFull:
+		}
+	}
+}
+
+// waitData waits until there is at least one value in the ring and returns the number of available values.
+// Returns io.EOF if the ring is closed and drained.
+func (e *RingEdge[T]) waitData(ctx context.Context, head uint64) (uint64, error) {
+	for spin := 0; ; spin++ {
+		if avail := e.tail.Load() - head; avail > 0 {
+			return avail, nil
+		}
+		if e.closed.Load() {
+			if avail := e.tail.Load() - head; avail > 0 {
+				return avail, nil
+			}
+			return 0, io.EOF
+		}
+		if spin < ringSpin {
+			runtime.Gosched()
+			continue
+		}
+
+		e.recvWait.Store(true)
+		if avail := e.tail.Load() - head; avail > 0 {
+			return avail, nil
+		}
+		select {
+		case <-ctx.Done():
+			return 0, context.Cause(ctx)
+		case <-e.notEmpty:
+		}
+	}
+}
+
+// signal performs a non-blocking notification on a channel with capacity of one.
+func signal(c chan struct{}) {
+	select {
+	case c <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,94 @@
+package graco_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/itohio/graco"
+)
+
+const (
+	benchCapacity = 1024
+	benchBatch    = 64
+)
+
+type nopNode struct {
+	name string
+}
+
+func (n nopNode) Close() error                    { return nil }
+func (n nopNode) Name() string                    { return n.name }
+func (n nopNode) Start(ctx context.Context) error { return nil }
+
+var (
+	benchSrc = nopNode{name: "src"}
+	benchDst = nopNode{name: "dst"}
+)
+
+func BenchmarkChannelEdge(b *testing.B) {
+	e, err := graco.NewSourceEdge[int]("channel", benchSrc, benchCapacity, false)
+	if err != nil {
+		b.Fatal(err)
+	}
+	e.Connect(benchDst)
+	benchEdge(b, e)
+}
+
+func BenchmarkRingEdge(b *testing.B) {
+	e, err := graco.NewRingEdge[int]("ring", benchSrc, benchCapacity)
+	if err != nil {
+		b.Fatal(err)
+	}
+	e.Connect(benchDst)
+	benchEdge(b, e)
+}
+
+func BenchmarkRingEdgeBatch(b *testing.B) {
+	e, err := graco.NewRingEdge[int]("ring", benchSrc, benchCapacity)
+	if err != nil {
+		b.Fatal(err)
+	}
+	e.Connect(benchDst)
+
+	ctx := context.Background()
+	b.ResetTimer()
+	go func() {
+		vals := make([]int, benchBatch)
+		for i := 0; i < b.N; i += benchBatch {
+			n := benchBatch
+			if b.N-i < n {
+				n = b.N - i
+			}
+			if _, err := e.SendN(ctx, vals[:n]); err != nil {
+				panic(err)
+			}
+		}
+	}()
+
+	vals := make([]int, benchBatch)
+	for i := 0; i < b.N; {
+		n, err := e.RecvN(ctx, vals)
+		if err != nil {
+			b.Fatal(err)
+		}
+		i += n
+	}
+}
+
+func benchEdge(b *testing.B, e graco.SourceEdge[int]) {
+	ctx := context.Background()
+	b.ResetTimer()
+	go func() {
+		for i := 0; i < b.N; i++ {
+			if err := e.Send(ctx, i); err != nil {
+				panic(err)
+			}
+		}
+	}()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := e.Recv(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package graco
+
+import "time"
+
+// Request is a value sent over a DestinationEdge by a requesting node.
+// ID correlates the request with its Response.
+type Request[T any] struct {
+	ID uint64
+	// Deadline is the time after which the requester is no longer interested in the reply. Zero means no deadline.
+	Deadline time.Time
+	Val      T
+}
+
+// Response is a value sent back over the reply edge of a DestinationEdge.
+// ID equals the ID of the Request it answers.
+type Response[T any] struct {
+	ID  uint64
+	Val T
+	Err error
+}
+
+// RequestEdge is a DestinationEdge carrying correlated requests and responses.
+type RequestEdge[Treq, Tresp any] DestinationEdge[Request[Treq], Response[Tresp]]
@@ -0,0 +1,85 @@
+package graco
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	_ Retainer = (*Shared[int])(nil)
+)
+
+// Retainer is an interface implemented by reference counted values that can be shared by several consumers without copying.
+// Every reference, including the initial one, is released with Close.
+type Retainer interface {
+	io.Closer
+	// Retain adds a reference.
+	Retain()
+}
+
+// Shared is a reference counted wrapper of a value. The release function is called when the last reference is released.
+// Nodes that drop values close them, which releases their reference.
+type Shared[T any] struct {
+	val     T
+	refs    atomic.Int32
+	release func(T)
+}
+
+// NewShared wraps a value with a single reference. release may be nil.
+func NewShared[T any](val T, release func(T)) *Shared[T] {
+	res := &Shared[T]{
+		val:     val,
+		release: release,
+	}
+	res.refs.Store(1)
+	if d := leakDetector.Load(); d != nil {
+		d.track(res)
+	}
+	return res
+}
+
+// NewSharedWithPool wraps a value that is put back to the pool when the last reference is released.
+// T should be a pointer type to avoid allocations when putting the value to the pool.
+func NewSharedWithPool[T any](p *sync.Pool, val T) *Shared[T] {
+	return NewShared(val, func(val T) {
+		p.Put(val)
+	})
+}
+
+// Val returns the wrapped value. It must not be used after the reference is released.
+func (s *Shared[T]) Val() T { return s.val }
+
+// Refs returns the number of references.
+func (s *Shared[T]) Refs() int { return int(s.refs.Load()) }
+
+func (s *Shared[T]) Retain() {
+	if s.refs.Add(1) <= 1 {
+		panic("retain of a released value")
+	}
+}
+
+// Release releases one reference. The value is released when no references remain.
+func (s *Shared[T]) Release() error {
+	refs := s.refs.Add(-1)
+	if refs < 0 {
+		return errors.New("released too many times")
+	}
+	if refs > 0 {
+		return nil
+	}
+	if d := leakDetector.Load(); d != nil {
+		d.untrack(s)
+	}
+	val := s.val
+	var zero T
+	s.val = zero
+	if s.release != nil {
+		s.release(val)
+	}
+	return nil
+}
+
+// Close releases one reference.
+func (s *Shared[T]) Close() error { return s.Release() }
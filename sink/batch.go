@@ -0,0 +1,87 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/itohio/graco"
+)
+
+// BatchSinkCloser is an optional interface of a SinkCloser that consumes whole batches at once.
+type BatchSinkCloser[T any] interface {
+	SinkCloser[T]
+	SinkBatch(context.Context, []T) error
+}
+
+// BatchNode is a batch-aware variant of Node. It receives values in batches of up to max values.
+// If the sinker implements BatchSinkCloser, the whole batch is passed to SinkBatch, otherwise Sink is called for each value.
+type BatchNode[T any] struct {
+	graco.NodeBase
+	name   string
+	input  graco.SourceEdge[T]
+	f      SinkCloser[T]
+	max    int
+	linger time.Duration
+}
+
+// NewBatch creates a BatchNode that collects up to max values or waits at most linger for a batch to fill.
+func NewBatch[T any](name string, max int, linger time.Duration, f SinkCloser[T]) *BatchNode[T] {
+	if max < 1 {
+		max = 1
+	}
+	res := &BatchNode[T]{
+		name:   name,
+		f:      f,
+		max:    max,
+		linger: linger,
+	}
+	return res
+}
+
+func (n *BatchNode[T]) Close() error {
+	return n.CloseOnce(func() error {
+		if n.f == nil {
+			return nil
+		}
+		return n.f.Close()
+	})
+}
+func (n *BatchNode[T]) Name() string { return n.name }
+
+func (n *BatchNode[T]) Connect(in graco.SourceEdge[T]) error {
+	n.input = in
+	return in.Connect(n)
+}
+
+func (n *BatchNode[T]) Start(ctx context.Context) error {
+	if err := graco.IsEdgeValid(n.input); err != nil {
+		return err
+	}
+	if n.f == nil {
+		return errors.New("sinker nil")
+	}
+
+	batcher, isBatcher := n.f.(BatchSinkCloser[T])
+	for {
+		vals, err := graco.RecvBatch(ctx, n.input, n.max, n.linger)
+		if err != nil {
+			return err
+		}
+		for _, val := range vals {
+			graco.Untrack(val)
+		}
+
+		if isBatcher {
+			if err := batcher.SinkBatch(ctx, vals); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, val := range vals {
+			if err := n.f.Sink(ctx, val); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package sink
+
+import (
+	"context"
+
+	"github.com/itohio/graco"
+)
+
+var (
+	_ SinkCloser[graco.Envelope[int]] = sinkEnvelopeWrapper[int]{}
+)
+
+type sinkEnvelopeWrapper[T any] struct {
+	f SinkCloser[T]
+}
+
+func (s sinkEnvelopeWrapper[T]) Close() error { return s.f.Close() }
+func (s sinkEnvelopeWrapper[T]) Sink(ctx context.Context, val graco.Envelope[T]) error {
+	return s.f.Sink(ctx, val.Val)
+}
+
+// Enveloped adapts a sinker of plain values to consume envelopes.
+func Enveloped[T any](f SinkCloser[T]) sinkEnvelopeWrapper[T] {
+	return sinkEnvelopeWrapper[T]{
+		f: f,
+	}
+}
@@ -0,0 +1,49 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/itohio/graco/codec"
+	"github.com/itohio/graco/processor"
+)
+
+var (
+	_ SinkCloser[processor.Blob[int]] = (*framesSink[int])(nil)
+)
+
+type flusher interface {
+	Flush() error
+}
+
+type framesSink[T any] struct {
+	w      io.Writer
+	framer codec.Framer
+}
+
+// Frames creates a sinker that writes blobs, e.g. produced by processor.Encode, to a byte stream using f.
+// Blobs are closed once written. On close the writer is flushed if it implements Flush, e.g. bufio.Writer,
+// and closed if it is an io.Closer.
+func Frames[T any](w io.Writer, f codec.Framer) *framesSink[T] {
+	return &framesSink[T]{
+		w:      w,
+		framer: f,
+	}
+}
+
+func (s *framesSink[T]) Close() error {
+	var err error
+	if f, ok := s.w.(flusher); ok {
+		err = f.Flush()
+	}
+	if closer, ok := s.w.(io.Closer); ok {
+		err = errors.Join(err, closer.Close())
+	}
+	return err
+}
+
+func (s *framesSink[T]) Sink(ctx context.Context, blob processor.Blob[T]) error {
+	err := s.framer.WriteFrame(s.w, blob.Data())
+	return errors.Join(err, blob.Close())
+}
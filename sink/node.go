@@ -3,6 +3,7 @@ package sink
 import (
 	"context"
 	"io"
+	"sync/atomic"
 
 	"github.com/itohio/graco"
 )
@@ -13,9 +14,11 @@ type SinkCloser[T any] interface {
 }
 
 type Node[T any] struct {
-	name  string
-	input graco.SourceEdge[T]
-	f     SinkCloser[T]
+	graco.NodeBase
+	name    string
+	input   graco.SourceEdge[T]
+	f       SinkCloser[T]
+	expired atomic.Uint64
 }
 
 func New[T any](name string) *Node[T] {
@@ -33,9 +36,19 @@ func NewFunc[T any](name string, f SinkCloser[T]) *Node[T] {
 	return res
 }
 
-func (n *Node[T]) Close() error { return n.f.Close() }
+func (n *Node[T]) Close() error {
+	return n.CloseOnce(func() error {
+		if n.f == nil {
+			return nil
+		}
+		return n.f.Close()
+	})
+}
 func (n *Node[T]) Name() string { return n.name }
 
+// Expired returns the number of expired values discarded by the node.
+func (n *Node[T]) Expired() uint64 { return n.expired.Load() }
+
 func (n *Node[T]) Connect(in graco.SourceEdge[T]) error {
 	n.input = in
 	return in.Connect(n)
@@ -52,10 +65,21 @@ func (n *Node[T]) Start(ctx context.Context) error {
 			return err
 		}
 
+		if expired, err := graco.DropExpired(val); expired {
+			n.expired.Add(1)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		graco.Untrack(val)
+
 		if n.f != nil {
 			if err := n.f.Sink(ctx, val); err != nil {
 				return err
 			}
+		} else if err := graco.Release(val); err != nil {
+			return err
 		}
 	}
 }
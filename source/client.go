@@ -0,0 +1,174 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/itohio/graco"
+)
+
+// ErrClientStopped is returned by Client.Call when the client node has stopped.
+var ErrClientStopped = errors.New("client stopped")
+
+// Client is a source node that sends correlated requests and matches replies to pending calls.
+// Call may be used concurrently from any goroutine. Once the node has stopped or is closed, Call returns ErrClientStopped.
+type Client[Treq, Tresp any] struct {
+	graco.NodeBase
+	name    string
+	cap     int
+	output  *graco.ChannelDestinationEdge[graco.Request[Treq], graco.Response[Tresp]]
+	reply   graco.SourceEdge[graco.Response[Tresp]]
+	id      atomic.Uint64
+	mu      sync.Mutex
+	pending map[uint64]chan graco.Response[Tresp]
+	running chan struct{}
+	done    chan struct{}
+
+	// sendMu is held for reading while a request is sent, so that Close does not close the request edge under Call.
+	sendMu  sync.RWMutex
+	closed  bool
+	stopped chan struct{}
+}
+
+// NewClient creates a Client node with request and reply edges of capacity cap.
+func NewClient[Treq, Tresp any](name string, cap int) *Client[Treq, Tresp] {
+	res := &Client[Treq, Tresp]{
+		name:    name,
+		cap:     cap,
+		pending: make(map[uint64]chan graco.Response[Tresp]),
+		running: make(chan struct{}),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	return res
+}
+
+func (n *Client[Treq, Tresp]) Close() error {
+	return n.CloseOnce(n.closeOutputs)
+}
+func (n *Client[Treq, Tresp]) Name() string { return n.name }
+
+func (n *Client[Treq, Tresp]) Connect() (graco.RequestEdge[Treq, Tresp], error) {
+	var err error
+	n.output, err = graco.NewDestinationEdge[graco.Request[Treq], graco.Response[Tresp]]("o", n, n.cap)
+	if err != nil {
+		return nil, err
+	}
+	n.reply, err = n.output.Reply()
+	return n.output, err
+}
+
+// closeOutputs makes further calls fail with ErrClientStopped and closes the request edge.
+func (n *Client[Treq, Tresp]) closeOutputs() error {
+	close(n.stopped)
+	if n.output == nil {
+		return nil
+	}
+	// Disconnecting unblocks calls waiting to send a request.
+	err := n.output.Disconnect()
+	n.sendMu.Lock()
+	n.closed = true
+	n.sendMu.Unlock()
+	return errors.Join(err, n.CloseOutputs(n.output))
+}
+
+// Start dispatches replies to pending calls until the context is canceled or the reply edge is closed.
+// The request edge is closed by Close rather than when Start returns, since Call may be used from other goroutines.
+func (n *Client[Treq, Tresp]) Start(ctx context.Context) error {
+	if err := graco.IsEdgeValid(n.output); err != nil {
+		return err
+	}
+	if err := graco.IsEdgeValid(n.reply); err != nil {
+		return err
+	}
+
+	close(n.running)
+	defer close(n.done)
+	for {
+		resp, err := n.reply.Recv(ctx)
+		if err != nil {
+			return err
+		}
+
+		n.mu.Lock()
+		c, ok := n.pending[resp.ID]
+		delete(n.pending, resp.ID)
+		n.mu.Unlock()
+		if ok {
+			c <- resp
+		}
+	}
+}
+
+// Call sends a request and waits for the matching reply.
+// If the node has not been started yet, Call waits for it to start.
+// The deadline of ctx, if any, is propagated with the request.
+func (n *Client[Treq, Tresp]) Call(ctx context.Context, req Treq) (Tresp, error) {
+	var zero Tresp
+	select {
+	case <-n.done:
+		return zero, ErrClientStopped
+	case <-n.stopped:
+		return zero, ErrClientStopped
+	default:
+	}
+	select {
+	case <-ctx.Done():
+		return zero, context.Cause(ctx)
+	case <-n.done:
+		return zero, ErrClientStopped
+	case <-n.stopped:
+		return zero, ErrClientStopped
+	case <-n.running:
+	}
+
+	id := n.id.Add(1)
+	c := make(chan graco.Response[Tresp], 1)
+	n.mu.Lock()
+	n.pending[id] = c
+	n.mu.Unlock()
+	defer func() {
+		n.mu.Lock()
+		delete(n.pending, id)
+		n.mu.Unlock()
+	}()
+
+	r := graco.Request[Treq]{
+		ID:  id,
+		Val: req,
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		r.Deadline = deadline
+	}
+	if err := n.send(ctx, r); err != nil {
+		return zero, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return zero, context.Cause(ctx)
+	case <-n.done:
+		return zero, ErrClientStopped
+	case <-n.stopped:
+		return zero, ErrClientStopped
+	case resp := <-c:
+		return resp.Val, resp.Err
+	}
+}
+
+// send sends a request unless the node is closed.
+func (n *Client[Treq, Tresp]) send(ctx context.Context, r graco.Request[Treq]) error {
+	n.sendMu.RLock()
+	defer n.sendMu.RUnlock()
+	if n.closed {
+		return ErrClientStopped
+	}
+	err := n.output.Send(ctx, r)
+	if errors.Is(err, io.EOF) {
+		return ErrClientStopped
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+package source_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/itohio/graco/source"
+)
+
+type nopNode struct{}
+
+func (nopNode) Close() error                    { return nil }
+func (nopNode) Name() string                    { return "nop" }
+func (nopNode) Start(ctx context.Context) error { return nil }
+
+func TestClientCallAfterClose(t *testing.T) {
+	c := source.NewClient[int, int]("client", 1)
+	out, err := c.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Connect(nopNode{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan error)
+	go func() { started <- c.Start(ctx) }()
+
+	// Calls racing with Close must fail instead of panicking.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := c.Call(context.Background(), i)
+			if !errors.Is(err, source.ErrClientStopped) {
+				t.Errorf("got %v, want ErrClientStopped", err)
+			}
+		}(i)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	cancel()
+	<-started
+
+	if _, err := c.Call(context.Background(), 1); !errors.Is(err, source.ErrClientStopped) {
+		t.Errorf("got %v, want ErrClientStopped", err)
+	}
+}
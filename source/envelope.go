@@ -0,0 +1,43 @@
+package source
+
+import (
+	"context"
+	"time"
+
+	"github.com/itohio/graco"
+)
+
+var (
+	_ SourceCloser[graco.Envelope[int]] = (*sourceEnvelopeWrapper[int])(nil)
+)
+
+type sourceEnvelopeWrapper[T any] struct {
+	f   SourceCloser[T]
+	seq uint64
+	ttl time.Duration
+}
+
+func (s *sourceEnvelopeWrapper[T]) Close() error { return s.f.Close() }
+func (s *sourceEnvelopeWrapper[T]) Source(ctx context.Context) (graco.Envelope[T], error) {
+	val, err := s.f.Source(ctx)
+	if err != nil {
+		return graco.Envelope[T]{}, err
+	}
+	s.seq++
+	res := graco.NewEnvelope(val)
+	res.Seq = s.seq
+	res.TraceID = graco.NewTraceID()
+	if s.ttl > 0 {
+		res.Deadline = res.Created.Add(s.ttl)
+	}
+	return res, nil
+}
+
+// Enveloped adapts a sourcer of plain values to produce envelopes with a sequence number, creation time and a trace ID.
+// If ttl is positive, the envelope deadline is set to ttl after creation.
+func Enveloped[T any](f SourceCloser[T], ttl time.Duration) *sourceEnvelopeWrapper[T] {
+	return &sourceEnvelopeWrapper[T]{
+		f:   f,
+		ttl: ttl,
+	}
+}
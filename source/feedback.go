@@ -0,0 +1,75 @@
+package source
+
+import (
+	"context"
+	"errors"
+
+	"github.com/itohio/graco"
+)
+
+// Feedback is a node that closes a loop in the graph. Its output is a feedback edge primed with initial values
+// that feeds the head of the loop, and its input is connected to the tail of the loop afterwards.
+type Feedback[T any] struct {
+	graco.NodeBase
+	name    string
+	cap     int
+	initial []T
+	input   graco.SourceEdge[T]
+	output  *graco.ChannelFeedbackEdge[T]
+}
+
+// NewFeedback creates a Feedback node with an output edge of capacity cap primed with initial values.
+func NewFeedback[T any](name string, cap int, initial ...T) *Feedback[T] {
+	res := &Feedback[T]{
+		name:    name,
+		cap:     cap,
+		initial: initial,
+	}
+	return res
+}
+
+func (n *Feedback[T]) Close() error {
+	return n.CloseOnce(n.closeOutputs)
+}
+func (n *Feedback[T]) Name() string { return n.name }
+
+func (n *Feedback[T]) closeOutputs() error {
+	if n.output == nil {
+		return nil
+	}
+	return n.CloseOutputs(n.output)
+}
+
+// Connect creates the feedback edge that must be connected to the head of the loop.
+func (n *Feedback[T]) Connect() (graco.SourceEdge[T], error) {
+	var err error
+	n.output, err = graco.NewFeedbackEdge[T]("o", n, n.cap, n.initial...)
+	return n.output, err
+}
+
+// ConnectFeedback connects the tail of the loop.
+func (n *Feedback[T]) ConnectFeedback(in graco.SourceEdge[T]) error {
+	n.input = in
+	return in.Connect(n)
+}
+
+func (n *Feedback[T]) Start(ctx context.Context) error {
+	defer n.closeOutputs()
+	if err := graco.IsEdgeValid(n.input); err != nil {
+		return err
+	}
+	if err := graco.IsEdgeValid(n.output); err != nil {
+		return err
+	}
+
+	for {
+		val, err := n.input.Recv(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := n.output.Send(ctx, val); err != nil {
+			return errors.Join(err, graco.Release(val))
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package source
+
+import (
+	"bufio"
+	"context"
+	"io"
+
+	"github.com/itohio/graco/codec"
+	"github.com/itohio/graco/processor"
+)
+
+var (
+	_ SourceCloser[processor.Blob[int]] = (*framesSource[int])(nil)
+)
+
+type framesSource[T any] struct {
+	r      io.Reader
+	br     *bufio.Reader
+	framer codec.Framer
+	pool   *processor.BlobPool
+	hint   int // size of the last frame
+}
+
+// Frames creates a sourcer that splits a byte stream into blobs using f. The blobs can be decoded with processor.Decode.
+// Blob buffers are taken from p if it is not nil, sized after the last frame. The reader is closed when the sourcer is closed, if it is an io.Closer.
+func Frames[T any](r io.Reader, f codec.Framer, p *processor.BlobPool) *framesSource[T] {
+	return &framesSource[T]{
+		r:      r,
+		br:     bufio.NewReader(r),
+		framer: f,
+		pool:   p,
+	}
+}
+
+func (s *framesSource[T]) Close() error {
+	if closer, ok := s.r.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Source reads the next frame. Returns io.EOF at the end of the stream.
+func (s *framesSource[T]) Source(ctx context.Context) (processor.Blob[T], error) {
+	var zero T
+	if s.pool == nil {
+		data, err := s.framer.ReadFrame(s.br, nil)
+		if err != nil {
+			return nil, err
+		}
+		return processor.NewBlob[T](zero, data), nil
+	}
+
+	buf := s.pool.Get(s.hint)
+	data, err := s.framer.ReadFrame(s.br, *buf)
+	*buf = data
+	if err != nil {
+		s.pool.Put(buf)
+		return nil, err
+	}
+	s.hint = len(data)
+	return processor.NewBlobWithPool[T](s.pool, zero, buf), nil
+}
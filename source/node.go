@@ -13,7 +13,15 @@ type SourceCloser[T any] interface {
 	Source(context.Context) (T, error)
 }
 
+// CreditSourceCloser is an optional interface of a SourceCloser that adapts to the credit available on a credit based output edge.
+// For example a sensor may lower its resolution when the consumer falls behind.
+type CreditSourceCloser[T any] interface {
+	SourceCloser[T]
+	SourceCredit(ctx context.Context, credit int) (T, error)
+}
+
 type Node[T any] struct {
+	graco.NodeBase
 	name   string
 	output graco.SourceEdge[T]
 	f      SourceCloser[T]
@@ -28,11 +36,13 @@ func New[T any](name string, f SourceCloser[T]) *Node[T] {
 }
 
 func (n *Node[T]) Close() error {
-	err := n.f.Close()
-	if n.output == nil {
-		return err
-	}
-	return errors.Join(err, n.output.Close())
+	return n.CloseOnce(func() error {
+		var err error
+		if n.f != nil {
+			err = n.f.Close()
+		}
+		return errors.Join(err, n.CloseOutputs(n.output))
+	})
 }
 func (n *Node[T]) Name() string { return n.name }
 
@@ -42,19 +52,50 @@ func (n *Node[T]) Connect() (graco.SourceEdge[T], error) {
 	return n.output, err
 }
 
+// ConnectTo uses an existing edge as the output. The node is attached as a producer if the edge supports several producers.
+func (n *Node[T]) ConnectTo(out graco.SourceEdge[T]) error {
+	if mp, ok := out.(graco.MultiProducerEdge); ok {
+		if err := mp.Attach(n); err != nil {
+			return err
+		}
+	}
+	n.output = out
+	return nil
+}
+
+// ConnectCredit creates a credit based output edge that allows window values in flight.
+// The consumer grants one credit back for each received value.
+func (n *Node[T]) ConnectCredit(window int) (graco.SourceEdge[T], error) {
+	var err error
+	n.output, err = graco.NewCreditEdge[T]("o", n, window, true)
+	return n.output, err
+}
+
 func (n *Node[T]) Start(ctx context.Context) error {
+	defer n.CloseOutputs(n.output)
 	if err := graco.IsEdgeValid(n.output); err != nil {
 		return err
 	}
 
+	crediter, hasCredit := n.output.(graco.Crediter)
+	creditSourcer, isCreditSourcer := n.f.(CreditSourceCloser[T])
 	for {
-		val, err := n.f.Source(ctx)
+		var (
+			val T
+			err error
+		)
+		if hasCredit && isCreditSourcer {
+			val, err = creditSourcer.SourceCredit(ctx, crediter.Credit())
+		} else {
+			val, err = n.f.Source(ctx)
+		}
 		if err != nil {
 			return err
 		}
+		graco.Track(val)
 
 		if err := n.output.Send(ctx, val); err != nil {
-			return err
+			return errors.Join(err, graco.Release(val))
 		}
 	}
 }
@@ -3,7 +3,8 @@ package source
 import "context"
 
 var (
-	_ SourceCloser[int] = sourceFuncWrapper[int]{}
+	_ SourceCloser[int]       = sourceFuncWrapper[int]{}
+	_ CreditSourceCloser[int] = sourceCreditFuncWrapper[int]{}
 )
 
 type sourceFuncWrapper[T any] struct {
@@ -18,3 +19,23 @@ func Func[T any](f func(context.Context) (T, error)) sourceFuncWrapper[T] {
 		f: f,
 	}
 }
+
+type sourceCreditFuncWrapper[T any] struct {
+	f func(context.Context, int) (T, error)
+}
+
+func (s sourceCreditFuncWrapper[T]) Close() error { return nil }
+func (s sourceCreditFuncWrapper[T]) Source(ctx context.Context) (T, error) {
+	return s.f(ctx, -1)
+}
+func (s sourceCreditFuncWrapper[T]) SourceCredit(ctx context.Context, credit int) (T, error) {
+	return s.f(ctx, credit)
+}
+
+// CreditFunc wraps a function that receives the credit available on the output edge.
+// Credit is -1 if the output edge is not credit based.
+func CreditFunc[T any](f func(ctx context.Context, credit int) (T, error)) sourceCreditFuncWrapper[T] {
+	return sourceCreditFuncWrapper[T]{
+		f: f,
+	}
+}
@@ -2,12 +2,14 @@ package throttle
 
 import (
 	"context"
+	"errors"
 	"io"
 
 	"github.com/itohio/graco"
 )
 
 type DropNode[T any] struct {
+	graco.NodeBase
 	name   string
 	input  graco.SourceEdge[T]
 	output graco.SourceEdge[T]
@@ -21,10 +23,7 @@ func NewDrop[T any](name string) *DropNode[T] {
 }
 
 func (n *DropNode[T]) Close() error {
-	if n.output == nil {
-		return nil
-	}
-	return n.output.Close()
+	return n.CloseOnce(func() error { return n.CloseOutputs(n.output) })
 }
 func (n *DropNode[T]) Name() string { return n.name }
 
@@ -39,6 +38,7 @@ func (n *DropNode[T]) Connect(in graco.SourceEdge[T]) (graco.SourceEdge[T], erro
 }
 
 func (n *DropNode[T]) Start(ctx context.Context) error {
+	defer n.CloseOutputs(n.output)
 	if err := graco.IsEdgeValid(n.input); err != nil {
 		return err
 	}
@@ -46,6 +46,10 @@ func (n *DropNode[T]) Start(ctx context.Context) error {
 		return err
 	}
 
+	var gone <-chan struct{}
+	if d, ok := n.output.(interface{ Disconnected() <-chan struct{} }); ok {
+		gone = d.Disconnected()
+	}
 	for {
 		val, err := n.input.Recv(ctx)
 		if err != nil {
@@ -55,12 +59,12 @@ func (n *DropNode[T]) Start(ctx context.Context) error {
 		select {
 		case <-ctx.Done():
 			return context.Cause(ctx)
+		case <-gone:
+			return errors.Join(io.EOF, graco.Release(val))
 		case n.output.C() <- val:
 		default:
-			if closer, ok := any(val).(io.Closer); ok {
-				if err := closer.Close(); err != nil {
-					return err
-				}
+			if err := graco.Release(val); err != nil {
+				return err
 			}
 		}
 	}
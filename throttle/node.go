@@ -2,18 +2,21 @@ package throttle
 
 import (
 	"context"
-	"io"
+	"errors"
+	"sync/atomic"
 	"time"
 
 	"github.com/itohio/graco"
 )
 
 type Node[T any] struct {
+	graco.NodeBase
 	name     string
 	input    graco.SourceEdge[T]
 	output   graco.SourceEdge[T]
 	interval time.Duration
 	drop     bool
+	expired  atomic.Uint64
 }
 
 func New[T any](name string, interval time.Duration, drop bool) *Node[T] {
@@ -26,13 +29,13 @@ func New[T any](name string, interval time.Duration, drop bool) *Node[T] {
 }
 
 func (n *Node[T]) Close() error {
-	if n.output == nil {
-		return nil
-	}
-	return n.output.Close()
+	return n.CloseOnce(func() error { return n.CloseOutputs(n.output) })
 }
 func (n *Node[T]) Name() string { return n.name }
 
+// Expired returns the number of expired values discarded by the node.
+func (n *Node[T]) Expired() uint64 { return n.expired.Load() }
+
 func (n *Node[T]) Connect(in graco.SourceEdge[T]) (graco.SourceEdge[T], error) {
 	n.input = in
 	err := in.Connect(n)
@@ -44,6 +47,7 @@ func (n *Node[T]) Connect(in graco.SourceEdge[T]) (graco.SourceEdge[T], error) {
 }
 
 func (n *Node[T]) Start(ctx context.Context) error {
+	defer n.CloseOutputs(n.output)
 	if err := graco.IsEdgeValid(n.input); err != nil {
 		return err
 	}
@@ -67,6 +71,15 @@ func (n *Node[T]) Start(ctx context.Context) error {
 			gotVal = true
 		}
 
+		if expired, err := graco.DropExpired(val); expired {
+			n.expired.Add(1)
+			if err != nil {
+				return err
+			}
+			gotVal = false
+			continue
+		}
+
 		now := time.Now()
 		delta := now.Sub(ts)
 		if delta > n.interval {
@@ -76,10 +89,8 @@ func (n *Node[T]) Start(ctx context.Context) error {
 
 		if counter >= 1 {
 			if n.drop {
-				if closer, ok := any(val).(io.Closer); ok {
-					if err := closer.Close(); err != nil {
-						return err
-					}
+				if err := graco.Release(val); err != nil {
+					return err
 				}
 				gotVal = false
 			}
@@ -87,7 +98,7 @@ func (n *Node[T]) Start(ctx context.Context) error {
 		}
 
 		if err := n.output.Send(ctx, val); err != nil {
-			return err
+			return errors.Join(err, graco.Release(val))
 		}
 		counter++
 	}
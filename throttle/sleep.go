@@ -2,12 +2,14 @@ package throttle
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/itohio/graco"
 )
 
 type SleeperNode[T any] struct {
+	graco.NodeBase
 	name     string
 	input    graco.SourceEdge[T]
 	output   graco.SourceEdge[T]
@@ -23,10 +25,7 @@ func NewSleeper[T any](name string, limit int, interval time.Duration) *SleeperN
 }
 
 func (n *SleeperNode[T]) Close() error {
-	if n.output == nil {
-		return nil
-	}
-	return n.output.Close()
+	return n.CloseOnce(func() error { return n.CloseOutputs(n.output) })
 }
 func (n *SleeperNode[T]) Name() string { return n.name }
 
@@ -41,6 +40,7 @@ func (n *SleeperNode[T]) Connect(in graco.SourceEdge[T]) (graco.SourceEdge[T], e
 }
 
 func (n *SleeperNode[T]) Start(ctx context.Context) error {
+	defer n.CloseOutputs(n.output)
 	if err := graco.IsEdgeValid(n.input); err != nil {
 		return err
 	}
@@ -55,7 +55,7 @@ func (n *SleeperNode[T]) Start(ctx context.Context) error {
 		}
 
 		if err := n.output.Send(ctx, val); err != nil {
-			return err
+			return errors.Join(err, graco.Release(val))
 		}
 
 		time.Sleep(n.interval)
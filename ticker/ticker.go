@@ -8,6 +8,7 @@ import (
 )
 
 type Node struct {
+	graco.NodeBase
 	name     string
 	output   graco.SourceEdge[int64]
 	interval time.Duration
@@ -22,10 +23,7 @@ func New(name string, interval time.Duration) *Node {
 }
 
 func (n *Node) Close() error {
-	if n.output == nil {
-		return nil
-	}
-	return n.output.Close()
+	return n.CloseOnce(func() error { return n.CloseOutputs(n.output) })
 }
 func (n *Node) Name() string { return n.name }
 
@@ -36,6 +34,7 @@ func (n *Node) Connect() (graco.SourceEdge[int64], error) {
 }
 
 func (n *Node) Start(ctx context.Context) error {
+	defer n.CloseOutputs(n.output)
 	if err := graco.IsEdgeValid(n.output); err != nil {
 		return err
 	}
@@ -0,0 +1,447 @@
+// Package wal provides a durable edge backed by an append-only segment log on local disk.
+package wal
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/itohio/graco"
+	"github.com/itohio/graco/codec"
+)
+
+var (
+	_ graco.SourceEdge[int] = (*Edge[int])(nil)
+)
+
+const (
+	segmentExt = ".wal"
+	ackFile    = "ack"
+	headerSize = 8
+
+	defaultSegmentSize = 64 << 20
+)
+
+var ErrCorrupt = errors.New("corrupt record")
+
+// Edge is a durable implementation of the SourceEdge[T] interface.
+// Values are appended to segment files and survive process crashes.
+// Delivery is at-least-once: values that were received but not acknowledged are delivered again after restart.
+//
+// Recv acknowledges the previously received value, which suits nodes that call Recv only after they have fully
+// handled the previous value. Next together with Ack can be used for explicit acknowledgement instead.
+//
+// Edge is not channel backed, therefore C returns nil.
+type Edge[T any] struct {
+	name        string
+	src, dst    graco.Node
+	dir         string
+	codec       codec.Codec[T]
+	segmentSize int64
+	notify      chan struct{}
+
+	mu       sync.Mutex
+	segments []uint64 // base offsets of the segments in ascending order
+	w        *os.File // last segment open for appending
+	wSize    int64
+	next     uint64 // offset of the next record to be written
+	acked    uint64 // offset of the first unacknowledged record
+	closed   bool
+
+	rmu       sync.Mutex // guards the reader against Close
+	r         *os.File   // segment being read
+	rbuf      *bufio.Reader
+	rSeg      uint64 // base offset of the segment being read
+	rOffset   uint64 // offset of the next record to be read
+	delivered bool   // whether a record was received by Recv and is pending acknowledgement
+}
+
+// New opens or creates a durable edge in dir. Reading resumes from the first unacknowledged offset.
+// segmentSize: Size in bytes after which a new segment file is started. Non-positive value selects 64 MiB.
+func New[T any](name string, src graco.Node, dir string, c codec.Codec[T], segmentSize int64) (*Edge[T], error) {
+	if segmentSize <= 0 {
+		segmentSize = defaultSegmentSize
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	res := &Edge[T]{
+		name:        name,
+		src:         src,
+		dir:         dir,
+		codec:       c,
+		segmentSize: segmentSize,
+		notify:      make(chan struct{}, 1),
+	}
+	if err := res.open(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (e *Edge[T]) Name() string                    { return e.name }
+func (e *Edge[T]) Nodes() (graco.Node, graco.Node) { return e.src, e.dst }
+func (e *Edge[T]) Connect(dst graco.Node) error {
+	e.dst = dst
+	return nil
+}
+func (e *Edge[T]) C() chan T { return nil }
+
+// Close stops accepting values and closes the segment files. Buffered values can still be received,
+// reopening the segment being read, after which Recv returns io.EOF.
+func (e *Edge[T]) Close() error {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return nil
+	}
+	e.closed = true
+	e.wake()
+	err := errors.Join(e.w.Sync(), e.w.Close())
+	e.mu.Unlock()
+
+	e.rmu.Lock()
+	defer e.rmu.Unlock()
+	return errors.Join(err, e.closeReader())
+}
+
+// Len returns the number of values that were written but not yet received.
+func (e *Edge[T]) Len() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return int(e.next - e.rOffset)
+}
+
+// Sync commits written segments to stable storage.
+func (e *Edge[T]) Sync() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil
+	}
+	return e.w.Sync()
+}
+
+func (e *Edge[T]) Send(ctx context.Context, val T) error {
+	if e.dst == nil {
+		return errors.New("output disconnected")
+	}
+	if err := ctx.Err(); err != nil {
+		return context.Cause(ctx)
+	}
+
+	data, err := e.codec.Marshal(val)
+	if err != nil {
+		return err
+	}
+	rec := make([]byte, headerSize+len(data))
+	binary.LittleEndian.PutUint32(rec[0:], uint32(len(data)))
+	binary.LittleEndian.PutUint32(rec[4:], crc32.ChecksumIEEE(data))
+	copy(rec[headerSize:], data)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return errors.New("edge closed")
+	}
+	if e.wSize > 0 && e.wSize+int64(len(rec)) > e.segmentSize {
+		if err := e.roll(); err != nil {
+			return err
+		}
+	}
+	n, err := e.w.Write(rec)
+	e.wSize += int64(n)
+	if err != nil {
+		return err
+	}
+	e.next++
+	e.wake()
+	return nil
+}
+
+// Recv acknowledges the previously received value and receives the next one.
+func (e *Edge[T]) Recv(ctx context.Context) (T, error) {
+	var zero T
+	if e.src == nil {
+		return zero, errors.New("input disconnected")
+	}
+
+	if e.delivered {
+		if err := e.Ack(e.rOffset - 1); err != nil {
+			return zero, err
+		}
+		e.delivered = false
+	}
+	val, _, err := e.Next(ctx)
+	if err != nil {
+		return zero, err
+	}
+	e.delivered = true
+	return val, nil
+}
+
+// Next receives the next value together with its offset without acknowledging anything.
+func (e *Edge[T]) Next(ctx context.Context) (T, uint64, error) {
+	var zero T
+	for {
+		e.mu.Lock()
+		avail := e.rOffset < e.next
+		closed := e.closed
+		e.mu.Unlock()
+
+		if avail {
+			break
+		}
+		if closed {
+			e.rmu.Lock()
+			err := e.closeReader()
+			e.rmu.Unlock()
+			if err != nil {
+				return zero, 0, errors.Join(io.EOF, err)
+			}
+			return zero, 0, io.EOF
+		}
+		select {
+		case <-ctx.Done():
+			return zero, 0, context.Cause(ctx)
+		case <-e.notify:
+		}
+	}
+
+	e.rmu.Lock()
+	data, err := e.readRecord()
+	e.rmu.Unlock()
+	if err != nil {
+		return zero, 0, err
+	}
+	e.mu.Lock()
+	offset := e.rOffset
+	e.rOffset++
+	e.mu.Unlock()
+
+	val, err := e.codec.Unmarshal(data)
+	return val, offset, err
+}
+
+// Ack acknowledges all values up to and including offset and removes segments that are no longer needed.
+func (e *Edge[T]) Ack(offset uint64) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if offset < e.acked {
+		return nil
+	}
+	e.acked = offset + 1
+
+	tmp := filepath.Join(e.dir, ackFile+".tmp")
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], e.acked)
+	if err := os.WriteFile(tmp, buf[:], 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(e.dir, ackFile)); err != nil {
+		return err
+	}
+
+	var err error
+	for len(e.segments) > 1 && e.segments[1] <= e.acked && e.segments[0] != e.rSeg {
+		err = errors.Join(err, os.Remove(e.segmentPath(e.segments[0])))
+		e.segments = e.segments[1:]
+	}
+	return err
+}
+
+func (e *Edge[T]) wake() {
+	select {
+	case e.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (e *Edge[T]) segmentPath(base uint64) string {
+	return filepath.Join(e.dir, fmt.Sprintf("%020d%s", base, segmentExt))
+}
+
+// open loads segments and acknowledged offset, repairs a torn tail and positions the reader.
+func (e *Edge[T]) open() error {
+	entries, err := os.ReadDir(e.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		base, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		e.segments = append(e.segments, base)
+	}
+	sort.Slice(e.segments, func(i, j int) bool { return e.segments[i] < e.segments[j] })
+
+	if data, err := os.ReadFile(filepath.Join(e.dir, ackFile)); err == nil && len(data) == 8 {
+		e.acked = binary.LittleEndian.Uint64(data)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if len(e.segments) == 0 {
+		e.segments = append(e.segments, e.acked)
+	}
+	if e.acked < e.segments[0] {
+		e.acked = e.segments[0]
+	}
+
+	last := e.segments[len(e.segments)-1]
+	e.w, err = os.OpenFile(e.segmentPath(last), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+	count, size, err := scanSegment(e.w)
+	if err != nil {
+		return err
+	}
+	if err := e.w.Truncate(size); err != nil {
+		return err
+	}
+	if _, err := e.w.Seek(size, io.SeekStart); err != nil {
+		return err
+	}
+	e.wSize = size
+	e.next = last + count
+	if e.acked > e.next {
+		e.acked = e.next
+	}
+
+	e.rOffset = e.acked
+	return nil
+}
+
+// roll starts a new segment. Must be called with the lock held.
+func (e *Edge[T]) roll() error {
+	f, err := os.OpenFile(e.segmentPath(e.next), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := e.w.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	e.w = f
+	e.wSize = 0
+	e.segments = append(e.segments, e.next)
+	return nil
+}
+
+// readRecord reads the record at rOffset, opening segments as needed.
+// Must only be called if the record has been written and with the reader lock held.
+func (e *Edge[T]) readRecord() ([]byte, error) {
+	for {
+		if e.r == nil {
+			if err := e.openReader(); err != nil {
+				return nil, err
+			}
+		}
+
+		var header [headerSize]byte
+		_, err := io.ReadFull(e.rbuf, header[:])
+		if errors.Is(err, io.EOF) {
+			// The segment is exhausted, the record is in the next one.
+			if err := e.closeReader(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, binary.LittleEndian.Uint32(header[0:]))
+		if _, err := io.ReadFull(e.rbuf, data); err != nil {
+			return nil, err
+		}
+		if crc32.ChecksumIEEE(data) != binary.LittleEndian.Uint32(header[4:]) {
+			return nil, ErrCorrupt
+		}
+		return data, nil
+	}
+}
+
+// openReader opens the segment containing rOffset and skips records preceding it.
+func (e *Edge[T]) openReader() error {
+	e.mu.Lock()
+	idx := sort.Search(len(e.segments), func(i int) bool { return e.segments[i] > e.rOffset }) - 1
+	if idx < 0 {
+		e.mu.Unlock()
+		return fmt.Errorf("offset %d not found", e.rOffset)
+	}
+	base := e.segments[idx]
+	e.rSeg = base
+	e.mu.Unlock()
+
+	f, err := os.Open(e.segmentPath(base))
+	if err != nil {
+		return err
+	}
+	e.r = f
+	e.rbuf = bufio.NewReader(f)
+
+	var header [headerSize]byte
+	for i := base; i < e.rOffset; i++ {
+		if _, err := io.ReadFull(e.rbuf, header[:]); err != nil {
+			return err
+		}
+		if _, err := e.rbuf.Discard(int(binary.LittleEndian.Uint32(header[0:]))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closeReader closes the segment being read. Must be called with the reader lock held.
+func (e *Edge[T]) closeReader() error {
+	if e.r == nil {
+		return nil
+	}
+	err := e.r.Close()
+	e.r = nil
+	e.rbuf = nil
+	return err
+}
+
+// scanSegment counts valid records in a segment and returns the size of the valid part.
+func scanSegment(f *os.File) (uint64, int64, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, 0, err
+	}
+	r := bufio.NewReader(f)
+	var (
+		count  uint64
+		size   int64
+		header [headerSize]byte
+	)
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return count, size, nil
+		}
+		data := make([]byte, binary.LittleEndian.Uint32(header[0:]))
+		if _, err := io.ReadFull(r, data); err != nil {
+			return count, size, nil
+		}
+		if crc32.ChecksumIEEE(data) != binary.LittleEndian.Uint32(header[4:]) {
+			return count, size, nil
+		}
+		count++
+		size += int64(headerSize + len(data))
+	}
+}
@@ -0,0 +1,124 @@
+package wal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/itohio/graco/codec"
+)
+
+type nopNode struct{}
+
+func (nopNode) Close() error                    { return nil }
+func (nopNode) Name() string                    { return "nop" }
+func (nopNode) Start(ctx context.Context) error { return nil }
+
+func open(t *testing.T, dir string, segmentSize int64) *Edge[int] {
+	t.Helper()
+	e, err := New[int]("wal", nopNode{}, dir, codec.JSON[int](), segmentSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Connect(nopNode{})
+	return e
+}
+
+func send(t *testing.T, e *Edge[int], vals ...int) {
+	t.Helper()
+	for _, val := range vals {
+		if err := e.Send(context.Background(), val); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func recv(t *testing.T, e *Edge[int], want ...int) {
+	t.Helper()
+	for _, w := range want {
+		val, err := e.Recv(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if val != w {
+			t.Fatalf("got %d, want %d", val, w)
+		}
+	}
+}
+
+func segments(t *testing.T, dir string) int {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(files)
+}
+
+func TestResumeFromAck(t *testing.T) {
+	dir := t.TempDir()
+	e := open(t, dir, 0)
+	send(t, e, 0, 1, 2, 3, 4, 5)
+	// Receiving 3 acknowledges 2, while 3 itself is not acknowledged.
+	recv(t, e, 0, 1, 2, 3)
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	e = open(t, dir, 0)
+	defer e.Close()
+	if n := e.Len(); n != 3 {
+		t.Errorf("got %d pending values, want 3", n)
+	}
+	recv(t, e, 3, 4, 5)
+}
+
+func TestTornTailRepair(t *testing.T) {
+	dir := t.TempDir()
+	e := open(t, dir, 0)
+	send(t, e, 0, 1, 2)
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a crash in the middle of appending a record.
+	f, err := os.OpenFile(e.segmentPath(0), os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte{42, 0, 0, 0, 1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	e = open(t, dir, 0)
+	defer e.Close()
+	if n := e.Len(); n != 3 {
+		t.Errorf("got %d pending values, want 3", n)
+	}
+	send(t, e, 3)
+	recv(t, e, 0, 1, 2, 3)
+}
+
+func TestSegmentDeletion(t *testing.T) {
+	dir := t.TempDir()
+	// A JSON encoded digit takes 9 bytes, therefore every segment holds two records.
+	e := open(t, dir, 18)
+	defer e.Close()
+	send(t, e, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	if n := segments(t, dir); n != 5 {
+		t.Fatalf("got %d segments, want 5", n)
+	}
+
+	recv(t, e, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	if err := e.Ack(9); err != nil {
+		t.Fatal(err)
+	}
+	// The segment being read is kept.
+	if n := segments(t, dir); n != 1 {
+		t.Errorf("got %d segments, want 1", n)
+	}
+}
@@ -0,0 +1,144 @@
+package window
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/itohio/graco"
+)
+
+// pane is a window covering keys in [start, end).
+type pane[T any] struct {
+	start, end int64
+	vals       []T
+}
+
+// panes is a set of aligned windows of a fixed size starting at multiples of hop.
+// Keys are value indices for count windows and nanoseconds for time windows.
+type panes[T any] struct {
+	size, hop int64
+	first     int64 // windows start at or after first
+	done      int64 // windows ending at or before done were emitted
+	open      []*pane[T]
+}
+
+func newPanes[T any](size, hop int64) panes[T] {
+	if hop <= 0 {
+		hop = size
+	}
+	return panes[T]{
+		size:  size,
+		hop:   hop,
+		first: minKey,
+		done:  minKey,
+	}
+}
+
+const minKey = -1 << 63
+
+// add appends val to every window containing key that was not emitted yet.
+// A value added to several windows is retained for each additional window if it is a graco.Retainer.
+// Returns false if no window accepted the value, in which case it is released.
+func (p *panes[T]) add(key int64, val T) (bool, error) {
+	n := 0
+	for start := floor(key, p.hop); start > key-p.size && start >= p.first; start -= p.hop {
+		if start+p.size <= p.done {
+			break
+		}
+		w := p.get(start)
+		if n > 0 {
+			if r, ok := any(val).(graco.Retainer); ok {
+				r.Retain()
+			}
+		}
+		w.vals = append(w.vals, val)
+		n++
+	}
+	if n == 0 {
+		return false, graco.Release(val)
+	}
+	return true, nil
+}
+
+// get returns the window starting at start, opening it if needed.
+func (p *panes[T]) get(start int64) *pane[T] {
+	i := sort.Search(len(p.open), func(i int) bool { return p.open[i].start >= start })
+	if i < len(p.open) && p.open[i].start == start {
+		return p.open[i]
+	}
+	w := &pane[T]{start: start, end: start + p.size}
+	p.open = append(p.open, nil)
+	copy(p.open[i+1:], p.open[i:])
+	p.open[i] = w
+	return w
+}
+
+// expire removes and returns windows ending at or before limit in order of their start.
+func (p *panes[T]) expire(limit int64) [][]T {
+	if limit > p.done {
+		p.done = limit
+	}
+	var res [][]T
+	i := 0
+	for _, w := range p.open {
+		if w.end <= limit {
+			res = append(res, w.vals)
+			continue
+		}
+		p.open[i] = w
+		i++
+	}
+	for j := i; j < len(p.open); j++ {
+		p.open[j] = nil
+	}
+	p.open = p.open[:i]
+	return res
+}
+
+// next returns the end of the earliest ending window.
+func (p *panes[T]) next() (int64, bool) {
+	if len(p.open) == 0 {
+		return 0, false
+	}
+	// Windows have equal size, so the first started ends first.
+	return p.open[0].end, true
+}
+
+// flush removes and returns all windows.
+func (p *panes[T]) flush() [][]T {
+	res := make([][]T, 0, len(p.open))
+	for _, w := range p.open {
+		if len(w.vals) > 0 {
+			res = append(res, w.vals)
+		}
+	}
+	p.open = nil
+	return res
+}
+
+// close releases values of all open windows.
+func (p *panes[T]) close() error {
+	var err error
+	for _, w := range p.flush() {
+		err = errors.Join(err, release(w))
+	}
+	return err
+}
+
+// release releases all values of a window.
+func release[T any](w []T) error {
+	var err error
+	for _, val := range w {
+		err = errors.Join(err, graco.Release(val))
+	}
+	return err
+}
+
+// floor rounds key down to a multiple of hop.
+func floor(key, hop int64) int64 {
+	r := key % hop
+	if r < 0 {
+		r += hop
+	}
+	return key - r
+}
@@ -0,0 +1,83 @@
+package window
+
+import (
+	"errors"
+	"time"
+
+	"github.com/itohio/graco"
+)
+
+var (
+	_ Windower[int] = (*sessionWindower[int])(nil)
+)
+
+// Session groups values into sessions separated by gaps of processing time longer than gap.
+// A session is emitted once no value arrived for gap.
+func Session[T any](gap time.Duration) *sessionWindower[T] {
+	return &sessionWindower[T]{gap: int64(gap)}
+}
+
+// EventSession groups values into sessions separated by gaps of event time longer than gap.
+// Values must implement fanin.WithTimestamp and are expected to arrive roughly in order.
+// A session is emitted when a value arrives after the gap, or when the input is exhausted.
+func EventSession[T any](gap time.Duration) *sessionWindower[T] {
+	return &sessionWindower[T]{gap: int64(gap), event: true}
+}
+
+type sessionWindower[T any] struct {
+	gap   int64
+	event bool
+	last  int64
+	vals  []T
+}
+
+func (w *sessionWindower[T]) Add(now time.Time, val T) ([][]T, bool, error) {
+	key := now.UnixNano()
+	if w.event {
+		ts, err := timestamp(val)
+		if err != nil {
+			return nil, false, errors.Join(err, graco.Release(val))
+		}
+		key = ts
+	}
+
+	var res [][]T
+	if len(w.vals) > 0 && key-w.last > w.gap {
+		res = append(res, w.vals)
+		w.vals = nil
+	}
+	w.vals = append(w.vals, val)
+	if len(w.vals) == 1 || key > w.last {
+		w.last = key
+	}
+	return res, true, nil
+}
+
+func (w *sessionWindower[T]) Expire(now time.Time) [][]T {
+	if w.event || len(w.vals) == 0 || now.UnixNano()-w.last < w.gap {
+		return nil
+	}
+	return w.Flush()
+}
+
+func (w *sessionWindower[T]) Deadline() time.Time {
+	if w.event || len(w.vals) == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, w.last+w.gap)
+}
+
+func (w *sessionWindower[T]) Flush() [][]T {
+	if len(w.vals) == 0 {
+		return nil
+	}
+	res := [][]T{w.vals}
+	w.vals = nil
+	return res
+}
+
+func (w *sessionWindower[T]) Close() error {
+	err := release(w.vals)
+	w.vals = nil
+	return err
+}
@@ -0,0 +1,206 @@
+// Package window provides nodes that group a stream of values into windows.
+//
+// Windows are defined by a Windower: by count, by processing time or by event time using fanin.WithTimestamp,
+// and either tumbling, sliding (hopping) or separated by session gaps. A window node emits every window as []T,
+// or a value reduced from it.
+//
+// Values belonging to several overlapping windows are shared between them rather than copied. Values implementing
+// io.Closer should implement graco.Retainer, e.g. graco.Shared, which is retained once for every additional window.
+package window
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/itohio/graco"
+	"github.com/itohio/graco/processor"
+)
+
+// Windower assigns values to windows and decides when windows are complete.
+type Windower[T any] interface {
+	io.Closer
+	// Add assigns a value received at now to windows and returns windows completed so far.
+	// Add always takes ownership of the value. Returns false if the value belongs to no window,
+	// e.g. because it is late, in which case it was released.
+	Add(now time.Time, val T) ([][]T, bool, error)
+	// Expire returns windows completed by processing time at now.
+	Expire(now time.Time) [][]T
+	// Deadline returns the time at which Expire must be called next. Zero time means there is no deadline.
+	Deadline() time.Time
+	// Flush returns all remaining windows, including incomplete ones. It is called when the input is exhausted.
+	Flush() [][]T
+}
+
+// Reducer reduces a window to a single value. It takes ownership of the values in the window only when it succeeds.
+// If it returns processor.ErrDrop or an error, the node releases the values.
+type Reducer[T, Res any] func(ctx context.Context, window []T) (Res, error)
+
+// Node groups input values into windows and emits a value for every window.
+type Node[T, Res any] struct {
+	graco.NodeBase
+	name     string
+	input    graco.SourceEdge[T]
+	output   graco.SourceEdge[Res]
+	windower Windower[T]
+	reduce   Reducer[T, Res]
+	expired  atomic.Uint64
+	dropped  atomic.Uint64
+}
+
+// New creates a node that emits every window as a slice of values.
+func New[T any](name string, w Windower[T]) *Node[T, []T] {
+	return NewReduce[T, []T](name, w, func(ctx context.Context, window []T) ([]T, error) {
+		return window, nil
+	})
+}
+
+// NewReduce creates a node that emits a value reduced from every window.
+func NewReduce[T, Res any](name string, w Windower[T], reduce Reducer[T, Res]) *Node[T, Res] {
+	res := &Node[T, Res]{
+		name:     name,
+		windower: w,
+		reduce:   reduce,
+	}
+	return res
+}
+
+// Close releases values of windows that were not emitted.
+func (n *Node[T, Res]) Close() error {
+	return n.CloseOnce(func() error {
+		var err error
+		if n.windower != nil {
+			err = n.windower.Close()
+		}
+		return errors.Join(err, n.CloseOutputs(n.output))
+	})
+}
+func (n *Node[T, Res]) Name() string { return n.name }
+
+// Expired returns the number of expired values discarded by the node.
+func (n *Node[T, Res]) Expired() uint64 { return n.expired.Load() }
+
+// Dropped returns the number of values that belonged to no window, e.g. late values.
+func (n *Node[T, Res]) Dropped() uint64 { return n.dropped.Load() }
+
+func (n *Node[T, Res]) Connect(in graco.SourceEdge[T]) (graco.SourceEdge[Res], error) {
+	n.input = in
+	err := in.Connect(n)
+	if err != nil {
+		return nil, err
+	}
+	n.output, err = graco.NewSourceEdge[Res]("o", n, 1, false)
+	return n.output, err
+}
+
+// Start groups input values until the input is exhausted, after which the remaining windows are emitted.
+func (n *Node[T, Res]) Start(ctx context.Context) error {
+	defer n.CloseOutputs(n.output)
+	if err := graco.IsEdgeValid(n.input); err != nil {
+		return err
+	}
+	if err := graco.IsEdgeValid(n.output); err != nil {
+		return err
+	}
+
+	if n.windower == nil || n.reduce == nil {
+		return errors.New("windower nil")
+	}
+
+	var (
+		rctx     = ctx
+		cancel   = func() {}
+		deadline time.Time
+	)
+	defer func() { cancel() }()
+	for {
+		// The receive context is renewed only when the deadline changes.
+		if d := n.windower.Deadline(); !d.Equal(deadline) {
+			cancel()
+			deadline = d
+			rctx, cancel = withDeadline(ctx, d)
+		}
+
+		val, err := n.input.Recv(rctx)
+		if errors.Is(err, io.EOF) {
+			if err := n.emit(ctx, n.windower.Flush()); err != nil {
+				return err
+			}
+			return err
+		}
+		if err != nil && ctx.Err() == nil && rctx.Err() != nil {
+			if err := n.emit(ctx, n.windower.Expire(time.Now())); err != nil {
+				return err
+			}
+			cancel()
+			rctx, cancel, deadline = ctx, func() {}, time.Time{}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if expired, err := graco.DropExpired(val); expired {
+			n.expired.Add(1)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		graco.Untrack(val)
+
+		windows, ok, err := n.windower.Add(time.Now(), val)
+		if !ok {
+			n.dropped.Add(1)
+		}
+		if err != nil {
+			return errors.Join(err, n.release(windows))
+		}
+		if err := n.emit(ctx, windows); err != nil {
+			return err
+		}
+	}
+}
+
+// withDeadline returns a context for receiving values that expires at d, unless d is zero.
+func withDeadline(ctx context.Context, d time.Time) (context.Context, context.CancelFunc) {
+	if d.IsZero() {
+		return ctx, func() {}
+	}
+	return context.WithDeadline(ctx, d)
+}
+
+func (n *Node[T, Res]) emit(ctx context.Context, windows [][]T) error {
+	for i, w := range windows {
+		res, err := n.reduce(ctx, w)
+		if errors.Is(err, processor.ErrDrop) {
+			if err := release(w); err != nil {
+				return errors.Join(err, n.release(windows[i+1:]))
+			}
+			continue
+		}
+		if err == nil {
+			graco.Track(res)
+			err = n.output.Send(ctx, res)
+			if err != nil {
+				err = errors.Join(err, graco.Release(res))
+			}
+		} else {
+			err = errors.Join(err, release(w))
+		}
+		if err != nil {
+			return errors.Join(err, n.release(windows[i+1:]))
+		}
+	}
+	return nil
+}
+
+func (n *Node[T, Res]) release(windows [][]T) error {
+	var err error
+	for _, w := range windows {
+		err = errors.Join(err, release(w))
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+package window
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/itohio/graco"
+	"github.com/itohio/graco/fanin"
+)
+
+var (
+	_ Windower[int]                 = (*countWindower[int])(nil)
+	_ Windower[int]                 = (*timeWindower[int])(nil)
+	_ Windower[graco.Envelope[int]] = (*eventWindower[graco.Envelope[int]])(nil)
+)
+
+// Count groups values into windows of size values starting every hop values.
+// hop equal to size gives tumbling windows, smaller hop gives sliding windows and larger hop skips values.
+// Incomplete windows are emitted when the input is exhausted.
+func Count[T any](size, hop int) (*countWindower[T], error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid window size %d", size)
+	}
+	res := &countWindower[T]{panes: newPanes[T](int64(size), int64(hop))}
+	res.first = 0
+	return res, nil
+}
+
+type countWindower[T any] struct {
+	panes[T]
+	index int64
+}
+
+func (w *countWindower[T]) Add(now time.Time, val T) ([][]T, bool, error) {
+	key := w.index
+	w.index++
+	ok, err := w.add(key, val)
+	return w.expire(w.index), ok, err
+}
+func (w *countWindower[T]) Expire(now time.Time) [][]T { return nil }
+func (w *countWindower[T]) Deadline() time.Time        { return time.Time{} }
+func (w *countWindower[T]) Flush() [][]T               { return w.flush() }
+func (w *countWindower[T]) Close() error               { return w.close() }
+
+// Time groups values into windows of processing time of length size starting every hop.
+// Windows are aligned to multiples of hop since Unix epoch. hop equal to size gives tumbling windows.
+// Windows are emitted when they end, even if no more values arrive.
+func Time[T any](size, hop time.Duration) (*timeWindower[T], error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid window size %v", size)
+	}
+	return &timeWindower[T]{panes: newPanes[T](int64(size), int64(hop))}, nil
+}
+
+type timeWindower[T any] struct {
+	panes[T]
+}
+
+func (w *timeWindower[T]) Add(now time.Time, val T) ([][]T, bool, error) {
+	key := now.UnixNano()
+	res := w.expire(key)
+	ok, err := w.add(key, val)
+	return res, ok, err
+}
+func (w *timeWindower[T]) Expire(now time.Time) [][]T { return w.expire(now.UnixNano()) }
+func (w *timeWindower[T]) Deadline() time.Time {
+	end, ok := w.next()
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(0, end)
+}
+func (w *timeWindower[T]) Flush() [][]T { return w.flush() }
+func (w *timeWindower[T]) Close() error { return w.close() }
+
+// EventTime groups values into windows of event time of length size starting every hop.
+// Values must implement fanin.WithTimestamp. Windows are aligned to multiples of hop.
+// A window is emitted once the watermark, the largest timestamp seen minus lateness, passes its end.
+// Values arriving after all of their windows were emitted are dropped.
+func EventTime[T any](size, hop, lateness time.Duration) (*eventWindower[T], error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid window size %v", size)
+	}
+	return &eventWindower[T]{
+		panes:    newPanes[T](int64(size), int64(hop)),
+		lateness: int64(lateness),
+		maxTs:    minKey,
+	}, nil
+}
+
+type eventWindower[T any] struct {
+	panes[T]
+	lateness int64
+	maxTs    int64
+}
+
+func (w *eventWindower[T]) Add(now time.Time, val T) ([][]T, bool, error) {
+	ts, err := timestamp(val)
+	if err != nil {
+		return nil, false, errors.Join(err, graco.Release(val))
+	}
+	ok, err := w.add(ts, val)
+	if ts > w.maxTs {
+		w.maxTs = ts
+	}
+	return w.expire(w.maxTs - w.lateness), ok, err
+}
+func (w *eventWindower[T]) Expire(now time.Time) [][]T { return nil }
+func (w *eventWindower[T]) Deadline() time.Time        { return time.Time{} }
+func (w *eventWindower[T]) Flush() [][]T               { return w.flush() }
+func (w *eventWindower[T]) Close() error               { return w.close() }
+
+func timestamp(val any) (int64, error) {
+	wts, ok := val.(fanin.WithTimestamp)
+	if !ok {
+		return 0, fmt.Errorf("%T does not implement fanin.WithTimestamp", val)
+	}
+	return int64(wts.Timestamp()), nil
+}